@@ -0,0 +1,186 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package fixtures loads testfixtures-style YAML files into a MySQL database for integration
+// tests, similar in spirit to go-testfixtures/testfixtures, but rendering placeholders with the
+// same strategy the rest of vsql_mysql uses rather than driving the driver directly.
+package fixtures
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/wojnosystems/vsql"
+	"github.com/wojnosystems/vsql/vparam"
+)
+
+// placeholderStrategy renders "?" placeholders, matching MySQL. Kept local to this package to
+// avoid importing vsql_mysql, which would create an import cycle if vsql_mysql ever depended on
+// fixtures for its own tests.
+type placeholderStrategy struct{}
+
+func (placeholderStrategy) InsertPlaceholderIntoSQL() string { return "?" }
+
+var defaultStrategy = placeholderStrategy{}
+
+// Loader loads fixture files into a database, guarded against running against the wrong schema.
+type Loader struct {
+	// Execer is where TRUNCATE/INSERT statements are sent, typically the vsql_mysql SQLer
+	Execer vsql.QueryExecer
+
+	// DBName is the database/schema name the caller's connection is pointed at
+	DBName string
+
+	// AllowedDBs is the safety guard: Load/LoadFiles refuse to run unless DBName is in this list, so
+	// a misconfigured CI run pointed at a production DSN can't wipe real data
+	AllowedDBs []string
+}
+
+// fixtureFile is one `<table>.yml`: a list of rows, each row a column-name to value map
+type fixtureFile struct {
+	table string
+	rows  []map[string]interface{}
+}
+
+// Load reads every `*.yml` file in dir and loads it via LoadFiles.
+func (l *Loader) Load(ctx context.Context, dir string) error {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.yml"))
+	if err != nil {
+		return err
+	}
+	return l.LoadFiles(ctx, matches...)
+}
+
+// LoadFiles loads each named YAML file, one per table, inside a single transaction where the
+// Execer supports it: FOREIGN_KEY_CHECKS is disabled, every named table is TRUNCATEd, the rows are
+// inserted, and checks are re-enabled, in that order.
+func (l *Loader) LoadFiles(ctx context.Context, files ...string) error {
+	if err := l.checkAllowed(ctx); err != nil {
+		return err
+	}
+
+	parsed := make([]fixtureFile, 0, len(files))
+	for _, f := range files {
+		ff, err := parseFixtureFile(f)
+		if err != nil {
+			return err
+		}
+		parsed = append(parsed, ff)
+	}
+
+	run := func(execer vsql.QueryExecer) error {
+		if _, err := execer.Exec(ctx, vparam.New("SET FOREIGN_KEY_CHECKS=0")); err != nil {
+			return err
+		}
+		for _, ff := range parsed {
+			if _, err := execer.Exec(ctx, vparam.New(fmt.Sprint("TRUNCATE TABLE `", ff.table, "`"))); err != nil {
+				return err
+			}
+			for _, row := range ff.rows {
+				q, args := insertQuery(ff.table, row)
+				if _, err := execer.Exec(ctx, vparam.NewAppendWithData(q, args...)); err != nil {
+					return err
+				}
+			}
+		}
+		_, err := execer.Exec(ctx, vparam.New("SET FOREIGN_KEY_CHECKS=1"))
+		return err
+	}
+
+	txStarter, ok := l.Execer.(vsql.SQLer)
+	if !ok {
+		return run(l.Execer)
+	}
+	return vsql.Txn(txStarter, ctx, nil, func(tx vsql.QueryExecer) (commit bool, err error) {
+		err = run(tx)
+		return err == nil, err
+	})
+}
+
+// MustLoad calls Load and panics on error; intended for TestMain, where there is no *testing.T to
+// report a failure through.
+func (l *Loader) MustLoad(ctx context.Context, dir string) {
+	if err := l.Load(ctx, dir); err != nil {
+		panic(fmt.Sprint("fixtures: MustLoad failed: ", err))
+	}
+}
+
+// checkAllowed confirms l.Execer's connection is actually pointed at l.DBName - not just that
+// l.DBName itself looks safe - before asking the caller whether that database is in AllowedDBs.
+// Trusting the caller-supplied DBName alone doesn't protect against a misconfigured DSN that
+// points at production while DBName still claims to be a test schema.
+func (l *Loader) checkAllowed(ctx context.Context) error {
+	rows, err := l.Execer.Query(ctx, vparam.New("SELECT DATABASE()"))
+	if err != nil {
+		return fmt.Errorf("fixtures: unable to determine the connected database: %w", err)
+	}
+	defer rows.Close()
+	row := rows.Next()
+	if row == nil {
+		return fmt.Errorf("fixtures: SELECT DATABASE() returned no rows")
+	}
+	var actual string
+	if err = row.Scan(&actual); err != nil {
+		return fmt.Errorf("fixtures: unable to read the connected database name: %w", err)
+	}
+	if actual != l.DBName {
+		return fmt.Errorf("fixtures: refusing to load, connection is on database %q but DBName is %q", actual, l.DBName)
+	}
+	for _, allowed := range l.AllowedDBs {
+		if allowed == actual {
+			return nil
+		}
+	}
+	return fmt.Errorf("fixtures: refusing to load into database %q, not present in AllowedDBs %v", actual, l.AllowedDBs)
+}
+
+func parseFixtureFile(path string) (ff fixtureFile, err error) {
+	base := filepath.Base(path)
+	ff.table = strings.TrimSuffix(base, filepath.Ext(base))
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ff, err
+	}
+	if err = yaml.Unmarshal(data, &ff.rows); err != nil {
+		return ff, fmt.Errorf("fixtures: unable to parse %s: %w", path, err)
+	}
+	return ff, nil
+}
+
+// insertQuery builds `INSERT INTO <table> (col1,col2) VALUES (?,?)` plus its args, in the stable
+// column order of row's keys sorted, so repeated runs generate identical SQL text for a given row shape.
+func insertQuery(table string, row map[string]interface{}) (q string, args []interface{}) {
+	cols := make([]string, 0, len(row))
+	for col := range row {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+
+	placeholders := make([]string, len(cols))
+	args = make([]interface{}, len(cols))
+	for i, col := range cols {
+		placeholders[i] = "?"
+		args[i] = row[col]
+	}
+	q = fmt.Sprint("INSERT INTO `", table, "` (`", strings.Join(cols, "`,`"), "`) VALUES (", strings.Join(placeholders, ","), ")")
+	return q, args
+}
@@ -0,0 +1,124 @@
+//Copyright 2019 Chris Wojno
+//
+//Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+//The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+//
+//THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package fixtures
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/wojnosystems/vsql/vparam"
+	"github.com/wojnosystems/vsql/vresult"
+	"github.com/wojnosystems/vsql/vrows"
+	"github.com/wojnosystems/vsql/vstmt"
+)
+
+// fakeExecer stands in for a real SQLer's connection, reporting dbName back for "SELECT
+// DATABASE()" so checkAllowed can be tested without a real MySQL server.
+type fakeExecer struct {
+	dbName string
+}
+
+func (f *fakeExecer) Query(ctx context.Context, query vparam.Queryer) (vrows.Rowser, error) {
+	return &fakeCurrentDBRows{value: f.dbName}, nil
+}
+
+func (f *fakeExecer) Insert(ctx context.Context, query vparam.Queryer) (vresult.InsertResulter, error) {
+	return nil, nil
+}
+
+func (f *fakeExecer) Exec(ctx context.Context, query vparam.Queryer) (vresult.Resulter, error) {
+	return nil, nil
+}
+
+func (f *fakeExecer) Prepare(ctx context.Context, query vparam.Queryer) (vstmt.Statementer, error) {
+	return nil, nil
+}
+
+// fakeCurrentDBRows is a one-row, one-column vrows.Rowser standing in for SELECT DATABASE()'s result
+type fakeCurrentDBRows struct {
+	value string
+	done  bool
+}
+
+func (r *fakeCurrentDBRows) Next() vrows.Rower {
+	if r.done {
+		return nil
+	}
+	r.done = true
+	return r
+}
+
+func (r *fakeCurrentDBRows) Scan(dest ...interface{}) error {
+	*dest[0].(*string) = r.value
+	return nil
+}
+
+func (r *fakeCurrentDBRows) Columns() []string { return []string{"DATABASE()"} }
+func (r *fakeCurrentDBRows) Close() error      { return nil }
+
+// a fixture file with a well-known shape should parse into the table name taken from its filename
+// and one row per YAML list entry
+func TestParseFixtureFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vsql_mysql_fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "users.yml")
+	err = ioutil.WriteFile(path, []byte(`
+- name: chris
+  age: 30
+- name: sam
+  age: 25
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ff, err := parseFixtureFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ff.table != "users" {
+		t.Errorf(`expected table "users", got %q`, ff.table)
+	}
+	if len(ff.rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(ff.rows))
+	}
+	if ff.rows[0]["name"] != "chris" {
+		t.Errorf(`expected first row name "chris", got %v`, ff.rows[0]["name"])
+	}
+}
+
+// the safety guard must refuse to load when the live connection's database isn't in AllowedDBs
+func TestLoader_checkAllowed(t *testing.T) {
+	ctx := context.Background()
+
+	l := &Loader{Execer: &fakeExecer{dbName: "production"}, DBName: "production", AllowedDBs: []string{"test_db"}}
+	if err := l.checkAllowed(ctx); err == nil {
+		t.Error("expected checkAllowed to refuse a database not present in AllowedDBs")
+	}
+
+	l = &Loader{Execer: &fakeExecer{dbName: "test_db"}, DBName: "test_db", AllowedDBs: []string{"test_db"}}
+	if err := l.checkAllowed(ctx); err != nil {
+		t.Errorf("expected checkAllowed to allow a listed database, got %v", err)
+	}
+}
+
+// the safety guard must refuse even a listed DBName when the live connection is actually pointed
+// somewhere else, e.g. a misconfigured DSN that leaves DBName claiming the wrong thing
+func TestLoader_checkAllowed_MismatchedConnection(t *testing.T) {
+	ctx := context.Background()
+	l := &Loader{Execer: &fakeExecer{dbName: "production"}, DBName: "test_db", AllowedDBs: []string{"test_db", "production"}}
+	if err := l.checkAllowed(ctx); err == nil {
+		t.Error("expected checkAllowed to refuse when the live connection doesn't match DBName")
+	}
+}
@@ -0,0 +1,343 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/wojnosystems/vsql"
+	"github.com/wojnosystems/vsql/ulong"
+	"github.com/wojnosystems/vsql/vparam"
+	"github.com/wojnosystems/vsql/vresult"
+	"github.com/wojnosystems/vsql/vrows"
+	"github.com/wojnosystems/vsql/vstmt"
+	"github.com/wojnosystems/vsql/vtxn"
+)
+
+// Client is the symmetrical counterpart to Server: it implements vsql.QueryExecTransactioner (and,
+// via its Begin/Prepare, vsql.SQLer) by sending Requests to a gateway server's HTTPS endpoint
+// instead of talking to a *sql.DB directly.
+type Client struct {
+	vsql.SQLer
+
+	// BaseURL is the gateway server's endpoint, e.g. "https://db-gateway.internal/query"
+	BaseURL string
+	// BearerToken, if set, is sent as "Authorization: Bearer <BearerToken>" on every request
+	BearerToken string
+	// HTTPClient is used to make requests; defaults to http.DefaultClient when nil
+	HTTPClient *http.Client
+
+	// handle is empty for the bare connection, or the transaction handle this Client was returned
+	// from Begin with
+	handle string
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+func (c *Client) do(ctx context.Context, req Request) (*http.Response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.BearerToken != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.BearerToken)
+	}
+	return c.httpClient().Do(httpReq)
+}
+
+func (c *Client) doJSON(ctx context.Context, req Request) (resp Response, err error) {
+	httpResp, err := c.do(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+	defer httpResp.Body.Close()
+	if err = json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return resp, err
+	}
+	if resp.Err != "" {
+		return resp, errors.New(resp.Err)
+	}
+	return resp, nil
+}
+
+// Ping see github.com/wojnosystems/vsql/interfaces.go#Pinger. Implemented as a no-op SELECT 1 exec
+// so the gateway endpoint doesn't need a dedicated health route.
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Exec(ctx, vparam.New("SELECT 1"))
+	return err
+}
+
+// Begin see github.com/wojnosystems/vsql/transactions.go#TransactionStarter. Asks the server to
+// open a transaction with the requested isolation level/read-only mode and returns a Client scoped
+// to the returned handle.
+func (c *Client) Begin(ctx context.Context, opts vtxn.TxOptioner) (tx vsql.QueryExecTransactioner, err error) {
+	req := Request{Kind: KindBegin}
+	if opts != nil {
+		req.TxIsolationLevel = opts.IsolationLevel()
+		req.TxReadOnly = opts.ReadOnly()
+	}
+	resp, err := c.doJSON(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{BaseURL: c.BaseURL, BearerToken: c.BearerToken, HTTPClient: c.HTTPClient, handle: resp.Handle}, nil
+}
+
+// Commit see github.com/wojnosystems/vsql/transactions.go#Transactioner
+func (c *Client) Commit() error {
+	_, err := c.doJSON(context.Background(), Request{Kind: KindCommit, Handle: c.handle})
+	return err
+}
+
+// Rollback see github.com/wojnosystems/vsql/transactions.go#Transactioner
+func (c *Client) Rollback() error {
+	_, err := c.doJSON(context.Background(), Request{Kind: KindRollback, Handle: c.handle})
+	return err
+}
+
+// Query see github.com/wojnosystems/vsql/interfaces.go#QueryExecer. Rows arrive as newline-delimited
+// JSON pages; memoryRows pulls one page at a time as Next is called instead of draining the whole
+// stream before returning, so the response body stays open for the lifetime of rRows.
+func (c *Client) Query(ctx context.Context, query vparam.Queryer) (rRows vrows.Rowser, err error) {
+	q, ps, err := query.Interpolate(query.SQLQueryUnInterpolated(), &passthroughStrategy)
+	if err != nil {
+		return nil, err
+	}
+	httpResp, err := c.do(ctx, Request{Kind: KindQuery, SQL: q, Args: ps, Handle: c.handle})
+	if err != nil {
+		return nil, err
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		defer httpResp.Body.Close()
+		return nil, fmt.Errorf("gateway: query request failed: %s", httpResp.Status)
+	}
+	return &memoryRows{pages: newRowPageDecoder(httpResp.Body)}, nil
+}
+
+// rowPageDecoder pulls one RowPage at a time off a KindQuery response body, shared by memoryRows
+// (client.go, a vrows.Rowser) and driverRows (driver.go, a database/sql/driver.Rows), so neither
+// adapter has to decode the whole result set into memory before the first row is available.
+type rowPageDecoder struct {
+	body    io.ReadCloser
+	dec     *json.Decoder
+	columns []string
+	pending [][]interface{}
+	idx     int
+	done    bool
+	err     error
+}
+
+func newRowPageDecoder(body io.ReadCloser) *rowPageDecoder {
+	return &rowPageDecoder{body: body, dec: json.NewDecoder(body)}
+}
+
+// fill fetches pages from body until either a non-empty page is pending or the stream is
+// exhausted/errors, so columns is known as soon as the first page arrives without requiring a row
+// to be consumed first.
+func (d *rowPageDecoder) fill() {
+	for d.idx >= len(d.pending) && !d.done {
+		if !d.dec.More() {
+			d.done = true
+			return
+		}
+		var page RowPage
+		if d.err = d.dec.Decode(&page); d.err != nil {
+			d.done = true
+			return
+		}
+		if page.Columns != nil {
+			d.columns = page.Columns
+		}
+		d.pending = page.Rows
+		d.idx = 0
+		if page.Done {
+			d.done = true
+		}
+	}
+}
+
+// columnsOf returns the columns captured on the first page, fetching it if nothing has been read
+// from body yet.
+func (d *rowPageDecoder) columnsOf() []string {
+	if d.columns == nil {
+		d.fill()
+	}
+	return d.columns
+}
+
+// next returns the next row's already-decoded values, or nil once the stream is exhausted. It
+// fetches additional pages from body as the current one runs out.
+func (d *rowPageDecoder) next() (row []interface{}, err error) {
+	d.fill()
+	if d.err != nil || d.idx >= len(d.pending) {
+		return nil, d.err
+	}
+	row = d.pending[d.idx]
+	d.idx++
+	return row, nil
+}
+
+func (d *rowPageDecoder) close() error {
+	return d.body.Close()
+}
+
+// Insert see github.com/wojnosystems/vsql/interfaces.go#QueryExecer
+func (c *Client) Insert(ctx context.Context, query vparam.Queryer) (res vresult.InsertResulter, err error) {
+	return c.exec(ctx, query, KindInsert)
+}
+
+// Exec see github.com/wojnosystems/vsql/interfaces.go#QueryExecer
+func (c *Client) Exec(ctx context.Context, query vparam.Queryer) (res vresult.Resulter, err error) {
+	return c.exec(ctx, query, KindExec)
+}
+
+func (c *Client) exec(ctx context.Context, query vparam.Queryer, kind Kind) (res *remoteResult, err error) {
+	q, ps, err := query.Interpolate(query.SQLQueryUnInterpolated(), &passthroughStrategy)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.doJSON(ctx, Request{Kind: kind, SQL: q, Args: ps, Handle: c.handle})
+	if err != nil {
+		return nil, err
+	}
+	return &remoteResult{lastInsertID: resp.LastInsertID, rowsAffected: resp.RowsAffected}, nil
+}
+
+// Prepare see github.com/wojnosystems/vsql/vstmt/statements.go#Preparer
+func (c *Client) Prepare(ctx context.Context, query vparam.Queryer) (stmtr vstmt.Statementer, err error) {
+	q := query.SQLQueryInterpolated(&passthroughStrategy)
+	resp, err := c.doJSON(ctx, Request{Kind: KindPrepare, SQL: q, Handle: c.handle})
+	if err != nil {
+		return nil, err
+	}
+	return &remoteStatement{client: c, handle: resp.Handle}, nil
+}
+
+// remoteStatement is a prepared statement handle living on the gateway server
+type remoteStatement struct {
+	vstmt.Statementer
+	client *Client
+	handle string
+}
+
+// Query see github.com/wojnosystems/vsql/vstmt/statements.go#Statementer
+func (s *remoteStatement) Query(ctx context.Context, query vparam.Parameterer) (rRows vrows.Rowser, err error) {
+	_, ps, err := query.Interpolate("", &passthroughStrategy)
+	if err != nil {
+		return nil, err
+	}
+	withHandle := &Client{BaseURL: s.client.BaseURL, BearerToken: s.client.BearerToken, HTTPClient: s.client.HTTPClient, handle: s.handle}
+	return withHandle.Query(ctx, vparam.NewAppendWithData("", ps...))
+}
+
+// Insert see github.com/wojnosystems/vsql/vstmt/statements.go#Statementer
+func (s *remoteStatement) Insert(ctx context.Context, query vparam.Parameterer) (res vresult.InsertResulter, err error) {
+	_, ps, err := query.Interpolate("", &passthroughStrategy)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.doJSON(ctx, Request{Kind: KindInsert, Args: ps, Handle: s.handle})
+	if err != nil {
+		return nil, err
+	}
+	return &remoteResult{lastInsertID: resp.LastInsertID, rowsAffected: resp.RowsAffected}, nil
+}
+
+// Exec see github.com/wojnosystems/vsql/vstmt/statements.go#Statementer
+func (s *remoteStatement) Exec(ctx context.Context, query vparam.Parameterer) (res vresult.Resulter, err error) {
+	_, ps, err := query.Interpolate("", &passthroughStrategy)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.doJSON(ctx, Request{Kind: KindExec, Args: ps, Handle: s.handle})
+	if err != nil {
+		return nil, err
+	}
+	return &remoteResult{lastInsertID: resp.LastInsertID, rowsAffected: resp.RowsAffected}, nil
+}
+
+// Close see github.com/wojnosystems/vsql/vstmt/statements.go#Statementer
+func (s *remoteStatement) Close() error {
+	_, err := s.client.doJSON(context.Background(), Request{Kind: KindStmtClose, Handle: s.handle})
+	return err
+}
+
+// remoteResult adapts a Response's LastInsertID/RowsAffected into vresult.InsertResulter/Resulter
+type remoteResult struct {
+	lastInsertID int64
+	rowsAffected int64
+}
+
+// LastInsertId see github.com/wojnosystems/vsql/vresult/results.go#InsertResulter
+func (r *remoteResult) LastInsertId() (ulong.ULong, error) { return ulong.NewInt64(r.lastInsertID), nil }
+
+// RowsAffected see github.com/wojnosystems/vsql/vresult/results.go#Resulter
+func (r *remoteResult) RowsAffected() (ulong.ULong, error) { return ulong.NewInt64(r.rowsAffected), nil }
+
+// memoryRows adapts the paged RowPage stream from Client.Query into a vrows.Rowser, pulling rows
+// from pages lazily on Next rather than pre-draining the whole response body up front.
+type memoryRows struct {
+	pages *rowPageDecoder
+	row   []interface{}
+}
+
+// Next see github.com/wojnosystems/vsql/vrows/rows.go#Rowser. Returns itself as the current Rower
+// until the stream is exhausted or errors, then nil.
+func (r *memoryRows) Next() vrows.Rower {
+	row, err := r.pages.next()
+	if err != nil || row == nil {
+		return nil
+	}
+	r.row = row
+	return r
+}
+
+// Scan copies the already-decoded values for the current row into dest, erroring on arity mismatch
+func (r *memoryRows) Scan(dest ...interface{}) error {
+	if len(dest) != len(r.row) {
+		return fmt.Errorf("gateway: Scan got %d destinations for %d columns", len(dest), len(r.row))
+	}
+	for i := range dest {
+		if err := assign(dest[i], r.row[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Columns returns the column names captured from the first streamed page
+func (r *memoryRows) Columns() []string {
+	return r.pages.columnsOf()
+}
+
+// Close releases the underlying HTTP response body
+func (r *memoryRows) Close() error { return r.pages.close() }
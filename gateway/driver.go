@@ -0,0 +1,210 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package gateway
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Connector implements database/sql/driver.Connector, producing Conns that speak this package's
+// HTTPS+JSON protocol instead of a wire-level database protocol. Pass a *Connector to
+// sql.OpenDB to get a *sql.DB backed by a gateway server - the same *sql.DB shape
+// vsql_engine_go.InstallSingle expects, so InstallMySQLGateway can wire a gateway up exactly the
+// way InstallMySQL wires up a direct MySQL connection.
+type Connector struct {
+	// BaseURL is the gateway server's endpoint, e.g. "https://db-gateway.internal/query"
+	BaseURL string
+	// BearerToken, if set, is sent as "Authorization: Bearer <BearerToken>" on every request
+	BearerToken string
+	// HTTPClient is used to make requests; defaults to http.DefaultClient when nil
+	HTTPClient *http.Client
+}
+
+// Connect see database/sql/driver.Connector
+func (c *Connector) Connect(ctx context.Context) (driver.Conn, error) {
+	return &conn{client: &Client{BaseURL: c.BaseURL, BearerToken: c.BearerToken, HTTPClient: c.HTTPClient}}, nil
+}
+
+// Driver see database/sql/driver.Connector
+func (c *Connector) Driver() driver.Driver {
+	return gatewayDriver{}
+}
+
+// gatewayDriver only exists to satisfy driver.Connector.Driver; this package is always opened via
+// sql.OpenDB(&Connector{...}), never sql.Open, since a gateway has no DSN string worth parsing.
+type gatewayDriver struct{}
+
+func (gatewayDriver) Open(name string) (driver.Conn, error) {
+	return nil, errors.New("gateway: use sql.OpenDB(&gateway.Connector{...}), not sql.Open")
+}
+
+// conn is a database/sql/driver.Conn backed by a gateway Client. client.handle is empty for the
+// bare connection and set to the transaction handle for the lifetime of a BeginTx.
+type conn struct {
+	client *Client
+}
+
+// Prepare see database/sql/driver.Conn. No round trip to the server happens here; the query text
+// is only sent once Exec/Query is actually called.
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{conn: c, query: query}, nil
+}
+
+// Close see database/sql/driver.Conn. There is no persistent resource to release; every request is
+// an independent HTTP call.
+func (c *conn) Close() error {
+	return nil
+}
+
+// Begin see database/sql/driver.Conn. Deprecated in favor of BeginTx, but still required by the
+// interface; database/sql only calls this when the caller didn't supply a context.
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx see database/sql/driver.ConnBeginTx. Asks the server to open a transaction and pins this
+// conn's client to the returned handle until Commit or Rollback.
+func (c *conn) BeginTx(ctx context.Context, _ driver.TxOptions) (driver.Tx, error) {
+	resp, err := c.client.doJSON(ctx, Request{Kind: KindBegin})
+	if err != nil {
+		return nil, err
+	}
+	c.client = &Client{BaseURL: c.client.BaseURL, BearerToken: c.client.BearerToken, HTTPClient: c.client.HTTPClient, handle: resp.Handle}
+	return &tx{conn: c}, nil
+}
+
+// tx is the database/sql/driver.Tx for a transaction opened by conn.BeginTx.
+type tx struct {
+	conn *conn
+}
+
+// Commit see database/sql/driver.Tx
+func (t *tx) Commit() error {
+	defer t.conn.clearHandle()
+	return t.conn.client.Commit()
+}
+
+// Rollback see database/sql/driver.Tx
+func (t *tx) Rollback() error {
+	defer t.conn.clearHandle()
+	return t.conn.client.Rollback()
+}
+
+// clearHandle drops the transaction handle pinned by BeginTx once the transaction ends, so later
+// statements on this conn run against the bare connection again.
+func (c *conn) clearHandle() {
+	c.client = &Client{BaseURL: c.client.BaseURL, BearerToken: c.client.BearerToken, HTTPClient: c.client.HTTPClient}
+}
+
+// stmt is a database/sql/driver.Stmt that sends its query text and args to the gateway on every
+// Exec/Query rather than preparing a statement server-side.
+type stmt struct {
+	conn  *conn
+	query string
+}
+
+// Close see database/sql/driver.Stmt
+func (s *stmt) Close() error {
+	return nil
+}
+
+// NumInput see database/sql/driver.Stmt. -1 tells database/sql not to sanity-check the argument
+// count, since this package never parses the query text to count placeholders.
+func (s *stmt) NumInput() int {
+	return -1
+}
+
+// Exec see database/sql/driver.Stmt
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	resp, err := s.conn.client.doJSON(context.Background(), Request{Kind: KindExec, SQL: s.query, Args: valuesToArgs(args), Handle: s.conn.client.handle})
+	if err != nil {
+		return nil, err
+	}
+	return &driverResult{lastInsertID: resp.LastInsertID, rowsAffected: resp.RowsAffected}, nil
+}
+
+// driverResult adapts a Response's LastInsertID/RowsAffected into database/sql/driver.Result, which
+// wants plain int64s - unlike remoteResult in client.go, which answers to vresult.InsertResulter/
+// Resulter's ulong.ULong-returning methods instead.
+type driverResult struct {
+	lastInsertID int64
+	rowsAffected int64
+}
+
+// LastInsertId see database/sql/driver.Result
+func (r *driverResult) LastInsertId() (int64, error) { return r.lastInsertID, nil }
+
+// RowsAffected see database/sql/driver.Result
+func (r *driverResult) RowsAffected() (int64, error) { return r.rowsAffected, nil }
+
+// Query see database/sql/driver.Stmt
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	httpResp, err := s.conn.client.do(context.Background(), Request{Kind: KindQuery, SQL: s.query, Args: valuesToArgs(args), Handle: s.conn.client.handle})
+	if err != nil {
+		return nil, err
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		defer httpResp.Body.Close()
+		return nil, fmt.Errorf("gateway: query request failed: %s", httpResp.Status)
+	}
+	return &driverRows{pages: newRowPageDecoder(httpResp.Body)}, nil
+}
+
+// valuesToArgs widens a driver.Value slice to []interface{} for JSON encoding in a Request
+func valuesToArgs(values []driver.Value) []interface{} {
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return args
+}
+
+// driverRows adapts the paged RowPage stream from a Query request into database/sql/driver.Rows,
+// pulling rows from pages lazily on Next rather than pre-draining the whole response body up front.
+type driverRows struct {
+	pages *rowPageDecoder
+}
+
+// Columns see database/sql/driver.Rows
+func (r *driverRows) Columns() []string {
+	return r.pages.columnsOf()
+}
+
+// Close see database/sql/driver.Rows
+func (r *driverRows) Close() error {
+	return r.pages.close()
+}
+
+// Next see database/sql/driver.Rows. Returns io.EOF once the stream is exhausted, or the decode
+// error if reading the next page fails.
+func (r *driverRows) Next(dest []driver.Value) error {
+	row, err := r.pages.next()
+	if err != nil {
+		return err
+	}
+	if row == nil {
+		return io.EOF
+	}
+	for i := range dest {
+		dest[i] = driver.Value(row[i])
+	}
+	return nil
+}
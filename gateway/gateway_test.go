@@ -0,0 +1,151 @@
+//Copyright 2019 Chris Wojno
+//
+//Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+//The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+//
+//THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package gateway_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/wojnosystems/vsql/vparam"
+	"github.com/wojnosystems/vsql_mysql/gateway"
+)
+
+// mustGatewayServer stands up an httptest.Server fronting an in-memory sqlite3 database via
+// gateway.Server, so Client/Connector round trips can be tested without a real MySQL server.
+func mustGatewayServer(t *testing.T, bearerToken string) (baseURL string, cleanup func()) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("unable to open the sqlite3 database", err)
+	}
+	if _, err = db.Exec(`CREATE TABLE widgets (id INTEGER PRIMARY KEY, name TEXT)`); err != nil {
+		t.Fatal("unable to create the widgets table", err)
+	}
+	srv := &gateway.Server{DB: db, BearerToken: bearerToken}
+	ts := httptest.NewServer(srv)
+	return ts.URL, func() {
+		ts.Close()
+		_ = srv.Close()
+		_ = db.Close()
+	}
+}
+
+// TestClient_InsertAndQuery round-trips an Insert and a Query through Client against a real
+// gateway.Server instance.
+func TestClient_InsertAndQuery(t *testing.T) {
+	baseURL, cleanup := mustGatewayServer(t, "s3cr3t")
+	defer cleanup()
+
+	c := &gateway.Client{BaseURL: baseURL, BearerToken: "s3cr3t"}
+	if _, err := c.Insert(context.Background(), vparam.NewAppendWithData("INSERT INTO widgets (name) VALUES (?)", "bolt")); err != nil {
+		t.Fatal("unable to insert via the gateway client", err)
+	}
+
+	rows, err := c.Query(context.Background(), vparam.New("SELECT name FROM widgets"))
+	if err != nil {
+		t.Fatal("unable to query via the gateway client", err)
+	}
+	defer rows.Close()
+	row := rows.Next()
+	if row == nil {
+		t.Fatal("expected at least one row")
+	}
+	var name string
+	if err = row.Scan(&name); err != nil {
+		t.Fatal("unable to scan the row", err)
+	}
+	if name != "bolt" {
+		t.Errorf(`expected name "bolt", got %q`, name)
+	}
+}
+
+// TestClient_WrongBearerTokenRejected confirms a mismatched bearer token is rejected, exercising
+// the constant-time comparison in Server.ServeHTTP.
+func TestClient_WrongBearerTokenRejected(t *testing.T) {
+	baseURL, cleanup := mustGatewayServer(t, "s3cr3t")
+	defer cleanup()
+
+	c := &gateway.Client{BaseURL: baseURL, BearerToken: "wrong"}
+	if _, err := c.Query(context.Background(), vparam.New("SELECT 1")); err == nil {
+		t.Fatal("expected an error for a request bearing the wrong bearer token")
+	}
+}
+
+// TestConnector_ExecAndQuery drives the same server through the database/sql/driver adapter, as
+// InstallMySQLGateway does via sql.OpenDB, rather than through Client directly.
+func TestConnector_ExecAndQuery(t *testing.T) {
+	baseURL, cleanup := mustGatewayServer(t, "")
+	defer cleanup()
+
+	db := sql.OpenDB(&gateway.Connector{BaseURL: baseURL})
+	defer db.Close()
+
+	if _, err := db.Exec("INSERT INTO widgets (name) VALUES (?)", "nut"); err != nil {
+		t.Fatal("unable to exec through the driver adapter", err)
+	}
+
+	var name string
+	if err := db.QueryRow("SELECT name FROM widgets").Scan(&name); err != nil {
+		t.Fatal("unable to query through the driver adapter", err)
+	}
+	if name != "nut" {
+		t.Errorf(`expected name "nut", got %q`, name)
+	}
+}
+
+// TestConnector_TransactionRollback confirms a rolled-back transaction through the driver adapter
+// doesn't persist, proving BeginTx/Commit/Rollback are wired to the same gateway handle.
+func TestConnector_TransactionRollback(t *testing.T) {
+	baseURL, cleanup := mustGatewayServer(t, "")
+	defer cleanup()
+
+	db := sql.OpenDB(&gateway.Connector{BaseURL: baseURL})
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal("unable to begin a transaction through the driver adapter", err)
+	}
+	if _, err = tx.Exec("INSERT INTO widgets (name) VALUES (?)", "screw"); err != nil {
+		t.Fatal("unable to insert within the transaction", err)
+	}
+	if err = tx.Rollback(); err != nil {
+		t.Fatal("unable to roll back", err)
+	}
+
+	var count int
+	if err = db.QueryRow("SELECT COUNT(*) FROM widgets WHERE name = ?", "screw").Scan(&count); err != nil {
+		t.Fatal("unable to count rows", err)
+	}
+	if count != 0 {
+		t.Errorf("expected the rolled-back insert not to survive, counted %d", count)
+	}
+}
+
+// TestServer_CloseTwice confirms a second Close doesn't panic closing an already-closed sweepStop.
+func TestServer_CloseTwice(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("unable to open the sqlite3 database", err)
+	}
+	defer db.Close()
+
+	srv := &gateway.Server{DB: db}
+	srv.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader("{}")))
+
+	if err = srv.Close(); err != nil {
+		t.Fatal("unable to close the server", err)
+	}
+	if err = srv.Close(); err != nil {
+		t.Fatal("unable to close the server a second time", err)
+	}
+}
@@ -0,0 +1,81 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package gateway exposes a vsql.SQLer over an authenticated HTTPS+JSON protocol so a database
+// connection can be shared with remote callers without handing out raw driver credentials, in the
+// spirit of cloudflared's now-retired db-connect proxy.
+package gateway
+
+import "database/sql"
+
+// Kind identifies what a Request asks the server to do.
+type Kind string
+
+const (
+	// KindQuery runs a SELECT and streams rows back
+	KindQuery Kind = "query"
+	// KindInsert runs an INSERT and returns the last insert ID plus rows affected
+	KindInsert Kind = "insert"
+	// KindExec runs an arbitrary statement and returns rows affected
+	KindExec Kind = "exec"
+	// KindPrepare prepares a statement against the current handle and returns a new statement handle
+	KindPrepare Kind = "prepare"
+	// KindBegin starts a transaction and returns a new transaction handle
+	KindBegin Kind = "begin"
+	// KindCommit commits the transaction identified by Handle
+	KindCommit Kind = "commit"
+	// KindRollback rolls back the transaction identified by Handle
+	KindRollback Kind = "rollback"
+	// KindStmtClose closes the prepared statement identified by Handle
+	KindStmtClose Kind = "stmt-close"
+)
+
+// Request is the JSON body POSTed to the gateway for every operation. SQL and Args are already the
+// interpolated query text and positional parameters produced by
+// vparam.Queryer.Interpolate(query.SQLQueryUnInterpolated(), &mySQLParamInterpolateStrategyDefault),
+// so the server never re-parses placeholders - it only forwards them to database/sql.
+type Request struct {
+	Kind Kind          `json:"kind"`
+	SQL  string        `json:"sql,omitempty"`
+	Args []interface{} `json:"args,omitempty"`
+
+	// Handle is the opaque transaction or statement handle this request executes against, issued by
+	// a prior KindBegin/KindPrepare response. Empty for requests against the bare connection.
+	Handle string `json:"handle,omitempty"`
+
+	// TxIsolationLevel and TxReadOnly carry a KindBegin request's vtxn.TxOptioner across the wire, so
+	// the server's db.BeginTx sees the same options the caller asked Client.Begin for.
+	TxIsolationLevel sql.IsolationLevel `json:"tx_isolation_level,omitempty"`
+	TxReadOnly       bool               `json:"tx_read_only,omitempty"`
+}
+
+// Response is returned for every Request. Rows are streamed separately as newline-delimited JSON
+// RowPage values following the headers when Kind was KindQuery; for every other Kind, Response is
+// the entire body.
+type Response struct {
+	Err          string `json:"err,omitempty"`
+	Handle       string `json:"handle,omitempty"`
+	LastInsertID int64  `json:"last_insert_id,omitempty"`
+	RowsAffected int64  `json:"rows_affected,omitempty"`
+}
+
+// RowPage is one page of a streamed query result. Columns is only populated on the first page that
+// carries a row, so vrows.Rowser implementations on the client don't have to re-read it for every
+// row; a result with no rows sends no column names at all.
+type RowPage struct {
+	Columns []string        `json:"columns,omitempty"`
+	Rows    [][]interface{} `json:"rows"`
+	Done    bool            `json:"done"`
+}
@@ -0,0 +1,374 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package gateway
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server fronts a *sql.DB with the gateway protocol. It owns every in-flight transaction and
+// prepared statement opened by a remote client, keyed by an opaque handle, and sweeps handles that
+// have gone idle so a client that disappears mid-transaction can't leak a connection forever.
+type Server struct {
+	// DB is the underlying connection pool this gateway serves
+	DB *sql.DB
+
+	// BearerToken, if non-empty, is required on every request's Authorization header as
+	// "Bearer <BearerToken>"
+	BearerToken string
+
+	// AllowedStatementPrefixes, if non-empty, restricts SQL to statements whose upper-cased, trimmed
+	// text starts with one of these prefixes (e.g. []string{"SELECT"} for a read-only replica). An
+	// empty list allows any statement.
+	AllowedStatementPrefixes []string
+
+	// IdleTimeout is how long a transaction or prepared statement may sit unused before the sweeper
+	// closes it. Defaults to 5 minutes when zero.
+	IdleTimeout time.Duration
+
+	mu      sync.Mutex
+	handles map[string]*handleEntry
+
+	sweepOnce sync.Once
+	sweepStop chan struct{}
+	closeOnce sync.Once
+}
+
+// handleEntry is either a transaction or a prepared statement, never both
+type handleEntry struct {
+	tx       *sql.Tx
+	stmt     *sql.Stmt
+	lastUsed time.Time
+}
+
+func (s *Server) idleTimeout() time.Duration {
+	if s.IdleTimeout <= 0 {
+		return 5 * time.Minute
+	}
+	return s.IdleTimeout
+}
+
+// startSweeper launches the background goroutine that closes idle handles. Safe to call many
+// times; only the first call does anything.
+func (s *Server) startSweeper() {
+	s.sweepOnce.Do(func() {
+		s.sweepStop = make(chan struct{})
+		go func() {
+			ticker := time.NewTicker(s.idleTimeout() / 2)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					s.sweepIdle()
+				case <-s.sweepStop:
+					return
+				}
+			}
+		}()
+	})
+}
+
+// Close stops the idle sweeper. It does not close s.DB, which the caller owns. Safe to call more
+// than once; only the first call closes sweepStop.
+func (s *Server) Close() error {
+	s.closeOnce.Do(func() {
+		if s.sweepStop != nil {
+			close(s.sweepStop)
+		}
+	})
+	return nil
+}
+
+func (s *Server) sweepIdle() {
+	cutoff := time.Now().Add(-s.idleTimeout())
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for handle, e := range s.handles {
+		if e.lastUsed.After(cutoff) {
+			continue
+		}
+		if e.tx != nil {
+			_ = e.tx.Rollback()
+		}
+		if e.stmt != nil {
+			_ = e.stmt.Close()
+		}
+		delete(s.handles, handle)
+	}
+}
+
+func newHandle() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ServeHTTP implements http.Handler, enforcing bearer-token auth before dispatching to handle().
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.startSweeper()
+	if s.BearerToken != "" {
+		auth := r.Header.Get("Authorization")
+		want := "Bearer " + s.BearerToken
+		if subtle.ConstantTimeCompare([]byte(auth), []byte(want)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	var req Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "malformed request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.SQL != "" && !s.statementAllowed(req.SQL) {
+		http.Error(w, "statement not allowed by gateway policy", http.StatusForbidden)
+		return
+	}
+
+	s.handle(r.Context(), w, req)
+}
+
+func (s *Server) statementAllowed(sqlText string) bool {
+	if len(s.AllowedStatementPrefixes) == 0 {
+		return true
+	}
+	trimmed := strings.ToUpper(strings.TrimSpace(sqlText))
+	for _, prefix := range s.AllowedStatementPrefixes {
+		if strings.HasPrefix(trimmed, strings.ToUpper(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) handle(ctx context.Context, w http.ResponseWriter, req Request) {
+	switch req.Kind {
+	case KindBegin:
+		s.handleBegin(w, req)
+	case KindCommit:
+		s.handleEndTx(w, req.Handle, true)
+	case KindRollback:
+		s.handleEndTx(w, req.Handle, false)
+	case KindPrepare:
+		s.handlePrepare(ctx, w, req)
+	case KindStmtClose:
+		s.handleStmtClose(w, req.Handle)
+	case KindQuery:
+		s.handleQuery(ctx, w, req)
+	case KindInsert, KindExec:
+		s.handleExec(ctx, w, req)
+	default:
+		http.Error(w, "unknown request kind", http.StatusBadRequest)
+	}
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// handleBegin opens a transaction that must outlive this single HTTP request, surviving until a
+// later KindCommit/KindRollback request arrives on the same handle. It deliberately does not use
+// req's request-scoped ctx: database/sql's Tx watches the context passed to BeginTx and
+// auto-rollbacks once that context is done, and req's ctx is canceled as soon as this handler
+// returns - which would silently kill the transaction before the client ever sent another request.
+func (s *Server) handleBegin(w http.ResponseWriter, req Request) {
+	tx, err := s.DB.BeginTx(context.Background(), &sql.TxOptions{Isolation: req.TxIsolationLevel, ReadOnly: req.TxReadOnly})
+	if err != nil {
+		s.writeJSON(w, Response{Err: err.Error()})
+		return
+	}
+	handle := newHandle()
+	s.mu.Lock()
+	if s.handles == nil {
+		s.handles = map[string]*handleEntry{}
+	}
+	s.handles[handle] = &handleEntry{tx: tx, lastUsed: time.Now()}
+	s.mu.Unlock()
+	s.writeJSON(w, Response{Handle: handle})
+}
+
+func (s *Server) handleEndTx(w http.ResponseWriter, handle string, commit bool) {
+	s.mu.Lock()
+	e, ok := s.handles[handle]
+	if ok {
+		delete(s.handles, handle)
+	}
+	s.mu.Unlock()
+	if !ok || e.tx == nil {
+		s.writeJSON(w, Response{Err: "unknown transaction handle"})
+		return
+	}
+	var err error
+	if commit {
+		err = e.tx.Commit()
+	} else {
+		err = e.tx.Rollback()
+	}
+	if err != nil {
+		s.writeJSON(w, Response{Err: err.Error()})
+		return
+	}
+	s.writeJSON(w, Response{})
+}
+
+func (s *Server) handlePrepare(ctx context.Context, w http.ResponseWriter, req Request) {
+	var stmt *sql.Stmt
+	var err error
+	if req.Handle != "" {
+		s.mu.Lock()
+		e, ok := s.handles[req.Handle]
+		s.mu.Unlock()
+		if !ok || e.tx == nil {
+			s.writeJSON(w, Response{Err: "unknown transaction handle"})
+			return
+		}
+		stmt, err = e.tx.PrepareContext(ctx, req.SQL)
+	} else {
+		stmt, err = s.DB.PrepareContext(ctx, req.SQL)
+	}
+	if err != nil {
+		s.writeJSON(w, Response{Err: err.Error()})
+		return
+	}
+	handle := newHandle()
+	s.mu.Lock()
+	if s.handles == nil {
+		s.handles = map[string]*handleEntry{}
+	}
+	s.handles[handle] = &handleEntry{stmt: stmt, lastUsed: time.Now()}
+	s.mu.Unlock()
+	s.writeJSON(w, Response{Handle: handle})
+}
+
+func (s *Server) handleStmtClose(w http.ResponseWriter, handle string) {
+	s.mu.Lock()
+	e, ok := s.handles[handle]
+	if ok {
+		delete(s.handles, handle)
+	}
+	s.mu.Unlock()
+	if !ok || e.stmt == nil {
+		s.writeJSON(w, Response{Err: "unknown statement handle"})
+		return
+	}
+	if err := e.stmt.Close(); err != nil {
+		s.writeJSON(w, Response{Err: err.Error()})
+		return
+	}
+	s.writeJSON(w, Response{})
+}
+
+func (s *Server) handleQuery(ctx context.Context, w http.ResponseWriter, req Request) {
+	rows, err := s.runQuery(ctx, req)
+	if err != nil {
+		s.writeJSON(w, Response{Err: err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		s.writeJSON(w, Response{Err: err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	enc := json.NewEncoder(w)
+	first := true
+	for rows.Next() {
+		vals := make([]interface{}, len(cols))
+		ptrs := make([]interface{}, len(cols))
+		for i := range vals {
+			ptrs[i] = &vals[i]
+		}
+		if err = rows.Scan(ptrs...); err != nil {
+			_ = enc.Encode(RowPage{Done: true})
+			return
+		}
+		page := RowPage{Rows: [][]interface{}{vals}}
+		if first {
+			page.Columns = cols
+			first = false
+		}
+		_ = enc.Encode(page)
+	}
+	_ = enc.Encode(RowPage{Done: true})
+}
+
+func (s *Server) runQuery(ctx context.Context, req Request) (*sql.Rows, error) {
+	if req.Handle != "" {
+		s.mu.Lock()
+		e, ok := s.handles[req.Handle]
+		if ok {
+			e.lastUsed = time.Now()
+		}
+		s.mu.Unlock()
+		if !ok {
+			return nil, errUnknownHandle
+		}
+		if e.tx != nil {
+			return e.tx.QueryContext(ctx, req.SQL, req.Args...)
+		}
+		return e.stmt.QueryContext(ctx, req.Args...)
+	}
+	return s.DB.QueryContext(ctx, req.SQL, req.Args...)
+}
+
+func (s *Server) handleExec(ctx context.Context, w http.ResponseWriter, req Request) {
+	res, err := s.runExec(ctx, req)
+	if err != nil {
+		s.writeJSON(w, Response{Err: err.Error()})
+		return
+	}
+	resp := Response{}
+	if id, err := res.LastInsertId(); err == nil {
+		resp.LastInsertID = id
+	}
+	if ra, err := res.RowsAffected(); err == nil {
+		resp.RowsAffected = ra
+	}
+	s.writeJSON(w, resp)
+}
+
+func (s *Server) runExec(ctx context.Context, req Request) (sql.Result, error) {
+	if req.Handle != "" {
+		s.mu.Lock()
+		e, ok := s.handles[req.Handle]
+		if ok {
+			e.lastUsed = time.Now()
+		}
+		s.mu.Unlock()
+		if !ok {
+			return nil, errUnknownHandle
+		}
+		if e.tx != nil {
+			return e.tx.ExecContext(ctx, req.SQL, req.Args...)
+		}
+		return e.stmt.ExecContext(ctx, req.Args...)
+	}
+	return s.DB.ExecContext(ctx, req.SQL, req.Args...)
+}
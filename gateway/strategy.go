@@ -0,0 +1,55 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package gateway
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/wojnosystems/vsql/interpolation_strategy"
+)
+
+// mysqlPlaceholderStrategy renders "?" placeholders, same as vsql_mysql's own strategy. The Client
+// keeps its own copy here rather than importing the vsql_mysql package, which would create an
+// import cycle (vsql_mysql imports this package for InstallMySQLGateway's gateway.Connector).
+type mysqlPlaceholderStrategy struct {
+	interpolation_strategy.InterpolateStrategy
+}
+
+func (m *mysqlPlaceholderStrategy) InsertPlaceholderIntoSQL() string {
+	return "?"
+}
+
+var passthroughStrategy = mysqlPlaceholderStrategy{}
+
+// assign copies src into the pointer dest in the same loosely-typed way database/sql.Rows.Scan
+// does for the common scalar types JSON can decode into.
+func assign(dest interface{}, src interface{}) error {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() {
+		return fmt.Errorf("gateway: Scan destination must be a non-nil pointer")
+	}
+	if src == nil {
+		return nil
+	}
+	sv := reflect.ValueOf(src)
+	ev := dv.Elem()
+	if sv.Type().ConvertibleTo(ev.Type()) {
+		ev.Set(sv.Convert(ev.Type()))
+		return nil
+	}
+	return fmt.Errorf("gateway: cannot assign %T into %T", src, dest)
+}
@@ -0,0 +1,141 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package migrate wraps github.com/golang-migrate/migrate/v4 so callers of vsql_mysql can run
+// schema migrations at startup without forcing that dependency on anyone who doesn't use it - the
+// runner lives behind the Runner interface and InstallMySQL itself never imports this package.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/mysql"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+// getLockID is the MySQL GET_LOCK() name every Runner acquires before running, so two app
+// instances starting at the same time don't race to apply migrations against the same database.
+const getLockID = "vsql_mysql:migrate"
+
+// getLockTimeoutSeconds bounds how long Up/Down/Goto/Force waits for the GET_LOCK before giving up.
+const getLockTimeoutSeconds = 30
+
+// Options configures a Runner.
+type Options struct {
+	// Source is a golang-migrate source URL, e.g. "file://migrations"
+	Source string
+}
+
+// Runner lets callers run schema migrations and report the applied version, kept as an interface
+// so code that doesn't want the golang-migrate dependency can depend on this instead of *Migrator.
+type Runner interface {
+	// Up applies all available up migrations
+	Up() error
+	// Down applies all available down migrations
+	Down() error
+	// Goto migrates to the given version, up or down as needed
+	Goto(version uint) error
+	// Force sets the recorded version without running any migration, for recovering from a dirty state
+	Force(version int) error
+	// Status reports the currently applied version and whether the last migration left it dirty
+	Status() (version uint, dirty bool, err error)
+}
+
+// Migrator is the default Runner, backed by golang-migrate's mysql database driver.
+type Migrator struct {
+	m  *migrate.Migrate
+	db *sql.DB
+}
+
+// New builds a Migrator against db using opts.Source as the migration file source. Up, Down, Goto
+// and Force each acquire MySQL's GET_LOCK(vsql_mysql:migrate, 30) for their duration, so concurrent
+// callers serialize instead of racing to apply the same migration twice; Status takes no lock.
+func New(db *sql.DB, opts Options) (*Migrator, error) {
+	driver, err := mysql.WithInstance(db, &mysql.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("migrate: unable to build mysql driver: %w", err)
+	}
+	m, err := migrate.NewWithDatabaseInstance(opts.Source, "mysql", driver)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: unable to initialize migrator: %w", err)
+	}
+	return &Migrator{m: m, db: db}, nil
+}
+
+// withLock pins a single *sql.Conn for the whole acquire/use/release sequence, since MySQL's
+// GET_LOCK/RELEASE_LOCK are scoped to the connection that holds them - acquiring and releasing
+// against the pooled *sql.DB could land the two calls on different physical connections, making
+// RELEASE_LOCK silently no-op and the lock never actually protect anything.
+func (r *Migrator) withLock(f func() error) error {
+	ctx := context.Background()
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("migrate: unable to acquire a connection: %w", err)
+	}
+	defer conn.Close()
+
+	var acquired int
+	row := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", getLockID, getLockTimeoutSeconds)
+	if err = row.Scan(&acquired); err != nil {
+		return fmt.Errorf("migrate: unable to acquire GET_LOCK: %w", err)
+	}
+	if acquired != 1 {
+		return fmt.Errorf("migrate: timed out waiting for GET_LOCK(%s)", getLockID)
+	}
+	defer conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", getLockID)
+	return f()
+}
+
+// Up see Runner
+func (r *Migrator) Up() error {
+	return r.withLock(func() error {
+		if err := r.m.Up(); err != nil && err != migrate.ErrNoChange {
+			return err
+		}
+		return nil
+	})
+}
+
+// Down see Runner
+func (r *Migrator) Down() error {
+	return r.withLock(func() error {
+		if err := r.m.Down(); err != nil && err != migrate.ErrNoChange {
+			return err
+		}
+		return nil
+	})
+}
+
+// Goto see Runner
+func (r *Migrator) Goto(version uint) error {
+	return r.withLock(func() error {
+		return r.m.Migrate(version)
+	})
+}
+
+// Force see Runner
+func (r *Migrator) Force(version int) error {
+	return r.withLock(func() error {
+		return r.m.Force(version)
+	})
+}
+
+// Status see Runner
+func (r *Migrator) Status() (version uint, dirty bool, err error) {
+	return r.m.Version()
+}
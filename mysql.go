@@ -17,6 +17,9 @@ package vsql_mysql
 
 import (
 	"database/sql"
+	"time"
+
+	"github.com/wojnosystems/vsql"
 	"github.com/wojnosystems/vsql/interpolation_strategy"
 	"github.com/wojnosystems/vsql_engine"
 	"github.com/wojnosystems/vsql_engine_go"
@@ -32,3 +35,60 @@ import (
 func InstallMySQL(engine vsql_engine.SingleTXer, db *sql.DB) {
 	vsql_engine_go.InstallSingle(engine, db, func() interpolation_strategy.InterpolateStrategy { return &mySQLParamInterpolateStrategyDefault })
 }
+
+// MySQLOptions configures NewMySQLWithOptions.
+type MySQLOptions struct {
+	// ClientSideNamedRewrite forces every query built with Named to route through this package's
+	// own `:name` rewriter (see rewriteNamed) and a prepared-statement execution path instead of
+	// handing `:name` tokens straight to the driver, analogous to lib/pq's
+	// binary_parameters=yes mode. Queries not built with Named fail, since only Named retains the
+	// raw SQL rewriteNamed needs. Leave false (the default) to keep the textual interpolation path
+	// NewMySQL already uses.
+	ClientSideNamedRewrite bool
+
+	// MaxOpenConns, MaxIdleConns, ConnMaxLifetime, and ConnMaxIdleTime configure the underlying
+	// *sql.DB's connection pool, mirroring the database/sql methods of the same name. A zero value
+	// leaves database/sql's own default for that setting in place.
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+
+	// RetryPolicy decides whether an error returned by a non-transactional Query/Insert/Exec is
+	// safe to retry after database/sql has had a chance to reconnect. Leave nil to use
+	// DefaultRetryPolicy.
+	RetryPolicy func(err error) bool
+}
+
+// NewMySQL creates a new SQLer backed by the *sql.DB dbFactory produces, using the same
+// driverFactory de-coupling rationale as InstallMySQL. If dbFactory returns nil, NewMySQL returns
+// nil.
+func NewMySQL(dbFactory func() *sql.DB) vsql.SQLer {
+	return NewMySQLWithOptions(dbFactory, MySQLOptions{})
+}
+
+// NewMySQLWithOptions is NewMySQL with MySQLOptions for opting into client-side named-parameter
+// rewriting (see MySQLOptions.ClientSideNamedRewrite), tuning the connection pool, and overriding
+// the retry policy used to reconnect after a dropped connection.
+func NewMySQLWithOptions(dbFactory func() *sql.DB, opts MySQLOptions) vsql.SQLer {
+	db := dbFactory()
+	if db == nil {
+		return nil
+	}
+	if opts.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(opts.MaxOpenConns)
+	}
+	if opts.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(opts.MaxIdleConns)
+	}
+	if opts.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(opts.ConnMaxLifetime)
+	}
+	if opts.ConnMaxIdleTime > 0 {
+		db.SetConnMaxIdleTime(opts.ConnMaxIdleTime)
+	}
+	if opts.RetryPolicy == nil {
+		opts.RetryPolicy = DefaultRetryPolicy
+	}
+	return &mySQL{db: db, opts: opts}
+}
@@ -0,0 +1,99 @@
+//Copyright 2019 Chris Wojno
+//
+//Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+//The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+//
+//THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package vsql_mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/wojnosystems/vsql/vparam"
+	"github.com/wojnosystems/vsql_mysql/mysqlerr"
+)
+
+// TestDuplicateKeyError inserts the same primary key twice and checks that the second Insert
+// surfaces mysqlerr.ErrDuplicateKey (1062) rather than an opaque driver error.
+func TestDuplicateKeyError(t *testing.T) {
+	s := mustConnect(t)
+
+	mustTemporaryTable(t, s, func(tableName string) {
+		insert := fmt.Sprintf("INSERT INTO `%s` (id,name,age) VALUES (1,?,?)", tableName)
+		if _, err := s.Insert(context.Background(), vparam.NewAppendWithData(insert, "chris", 21)); err != nil {
+			t.Fatal("unable to insert the first row", err)
+		}
+
+		_, err := s.Insert(context.Background(), vparam.NewAppendWithData(insert, "chris", 21))
+		if !errors.Is(err, mysqlerr.ErrDuplicateKey) {
+			t.Fatalf("expected mysqlerr.ErrDuplicateKey for a repeated primary key, got %v", err)
+		}
+		var mErr *mysqlerr.MySQLError
+		if !errors.As(err, &mErr) {
+			t.Fatalf("expected errors.As to find a *mysqlerr.MySQLError, got %v", err)
+		}
+		if mErr.Code != 1062 {
+			t.Errorf("expected Code 1062, got %d", mErr.Code)
+		}
+	})
+}
+
+// TestDeadlockError forces two transactions to lock the same two rows in opposite order so InnoDB
+// picks one as the deadlock victim, and checks that its UPDATE (run through Exec) surfaces
+// mysqlerr.ErrDeadlock (1213) rather than an opaque driver error.
+func TestDeadlockError(t *testing.T) {
+	s := mustConnect(t)
+
+	mustTemporaryTable(t, s, func(tableName string) {
+		insert := fmt.Sprintf("INSERT INTO `%s` (id,name,age) VALUES (?,?,?)", tableName)
+		if _, err := s.Exec(context.Background(), vparam.NewAppendWithData(insert, 1, "a", 1)); err != nil {
+			t.Fatal("unable to seed row 1", err)
+		}
+		if _, err := s.Exec(context.Background(), vparam.NewAppendWithData(insert, 2, "b", 2)); err != nil {
+			t.Fatal("unable to seed row 2", err)
+		}
+
+		update := fmt.Sprintf("UPDATE `%s` SET age=age+1 WHERE id=?", tableName)
+
+		txA, err := s.Begin(context.Background(), nil)
+		if err != nil {
+			t.Fatal("unable to start transaction A", err)
+		}
+		txB, err := s.Begin(context.Background(), nil)
+		if err != nil {
+			t.Fatal("unable to start transaction B", err)
+		}
+
+		if _, err = txA.Exec(context.Background(), vparam.NewAppendWithData(update, 1)); err != nil {
+			t.Fatal("transaction A unable to lock row 1", err)
+		}
+		if _, err = txB.Exec(context.Background(), vparam.NewAppendWithData(update, 2)); err != nil {
+			t.Fatal("transaction B unable to lock row 2", err)
+		}
+
+		var wg sync.WaitGroup
+		var errA, errB error
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, errA = txA.Exec(context.Background(), vparam.NewAppendWithData(update, 2))
+		}()
+		go func() {
+			defer wg.Done()
+			_, errB = txB.Exec(context.Background(), vparam.NewAppendWithData(update, 1))
+		}()
+		wg.Wait()
+		_ = txA.Rollback()
+		_ = txB.Rollback()
+
+		if !errors.Is(errA, mysqlerr.ErrDeadlock) && !errors.Is(errB, mysqlerr.ErrDeadlock) {
+			t.Fatalf("expected one side of the crossed updates to surface mysqlerr.ErrDeadlock, got errA=%v errB=%v", errA, errB)
+		}
+	})
+}
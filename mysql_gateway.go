@@ -0,0 +1,36 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package vsql_mysql
+
+import (
+	"database/sql"
+
+	"github.com/wojnosystems/vsql/interpolation_strategy"
+	"github.com/wojnosystems/vsql_engine"
+	"github.com/wojnosystems/vsql_engine_go"
+	"github.com/wojnosystems/vsql_mysql/gateway"
+)
+
+// InstallMySQLGateway wires engine up to a remote vsql_mysql/gateway server instead of a local
+// *sql.DB, for callers that want to reach a database over the gateway's authenticated HTTPS+JSON
+// protocol rather than opening a direct MySQL connection. baseURL and bearerToken are passed
+// straight through to a gateway.Connector, which sql.OpenDB turns into an ordinary *sql.DB so this
+// can be handed to vsql_engine_go.InstallSingle exactly the way InstallMySQL wires up a direct
+// MySQL connection.
+func InstallMySQLGateway(engine vsql_engine.SingleTXer, baseURL string, bearerToken string) {
+	db := sql.OpenDB(&gateway.Connector{BaseURL: baseURL, BearerToken: bearerToken})
+	vsql_engine_go.InstallSingle(engine, db, func() interpolation_strategy.InterpolateStrategy { return &mySQLParamInterpolateStrategyDefault })
+}
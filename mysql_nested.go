@@ -0,0 +1,35 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package vsql_mysql
+
+import (
+	"database/sql"
+
+	"github.com/wojnosystems/vsql/interpolation_strategy"
+	"github.com/wojnosystems/vsql_engine"
+	"github.com/wojnosystems/vsql_engine_go"
+)
+
+// InstallMySQLNested wires up a SQLer backed by db that supports nested transactions via MySQL
+// SAVEPOINTs. Use this instead of InstallMySQL when your engine implements vsql_engine.MultiTXer
+// and you need to Begin a transaction while already inside one (e.g. a service method that starts
+// its own transaction being called from a caller that already started one). Every nested Begin
+// issues `SAVEPOINT sp_<n>` against the single underlying *sql.Tx; Commit and Rollback at that
+// depth issue RELEASE/ROLLBACK TO SAVEPOINT instead of touching the real connection-level
+// transaction.
+func InstallMySQLNested(engine vsql_engine.MultiTXer, db *sql.DB) {
+	vsql_engine_go.InstallMulti(engine, db, func() interpolation_strategy.InterpolateStrategy { return &mySQLParamInterpolateStrategyDefault })
+}
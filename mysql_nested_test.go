@@ -0,0 +1,93 @@
+//Copyright 2019 Chris Wojno
+//
+//Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+//The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+//
+//THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package vsql_mysql
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/wojnosystems/vsql/aggregator"
+	"github.com/wojnosystems/vsql/vparam"
+)
+
+// mustConnectNested opens the same MySQL/MariaDB server as mustConnect, using the same environment
+// variables, but wraps it in mySQLNested so tests here can exercise SAVEPOINT nesting against the
+// real docker-compose database.
+func mustConnectNested(t *testing.T) *mySQLNested {
+	cfg := mysql.Config{
+		User:                 os.Getenv("MYSQL_USER"),
+		Passwd:               os.Getenv("MYSQL_PASSWORD"),
+		Addr:                 os.Getenv("MYSQL_ADDR"),
+		DBName:               os.Getenv("MYSQL_DBNAME"),
+		AllowNativePasswords: true,
+		AllowOldPasswords:    true,
+	}
+	if strings.HasPrefix(cfg.Addr, "unix") {
+		cfg.Net = "unix"
+	} else {
+		cfg.Net = "tcp"
+	}
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatal("unable to initialize the MySQL driver", err)
+		return nil
+	}
+	return &mySQLNested{db: db}
+}
+
+// start an outer transaction, nest one via SAVEPOINT, roll back only the nested part, and confirm
+// the outer insert survives while the nested one does not
+func TestTransactionNested_PartialRollback(t *testing.T) {
+	root := mustConnectNested(t)
+
+	mustTemporaryTable(t, root, func(tableName string) {
+		outerTx, err := root.Begin(context.Background(), nil)
+		if err != nil {
+			t.Fatal("unable to start outer transaction", err)
+		}
+
+		_, err = outerTx.Insert(context.Background(), vparam.NewAppendWithData("INSERT INTO `"+tableName+"` (name,age) VALUES (?,?)", "chris", 21))
+		if err != nil {
+			t.Fatal("Error not expected when inserting data in the outer transaction", err)
+		}
+
+		nested, ok := outerTx.(*mySQLtxNested)
+		if !ok {
+			t.Fatal("outer transaction did not return a *mySQLtxNested")
+		}
+
+		innerTx, err := nested.Begin(context.Background(), nil)
+		if err != nil {
+			t.Fatal("unable to start nested transaction", err)
+		}
+		_, err = innerTx.Insert(context.Background(), vparam.NewAppendWithData("INSERT INTO `"+tableName+"` (name,age) VALUES (?,?)", "sam", 25))
+		if err != nil {
+			t.Fatal("Error not expected when inserting data in the nested transaction", err)
+		}
+		if err = innerTx.Rollback(); err != nil {
+			t.Fatal("unable to roll back to savepoint", err)
+		}
+
+		if err = outerTx.Commit(); err != nil {
+			t.Fatal("unable to commit outer transaction", err)
+		}
+
+		count, err := aggregator.Count(context.Background(), root, vparam.New("SELECT COUNT(*) FROM `"+tableName+"`"))
+		if err != nil {
+			t.Error("Error not expected when counting data")
+		}
+		if 1 != count {
+			t.Errorf(`Expected only the outer insert to survive, but counted %d rows`, count)
+		}
+	})
+}
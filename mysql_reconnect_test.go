@@ -0,0 +1,62 @@
+//Copyright 2019 Chris Wojno
+//
+//Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+//The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+//
+//THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package vsql_mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/wojnosystems/vsql/vparam"
+)
+
+// TestReconnect kills the server-side connection backing an in-flight transaction via
+// KILL CONNECTION_ID(), mirroring the lib/pq TestReconnect pattern: the transaction must surface
+// ErrConnectionLost instead of hanging or silently losing the caller's writes, and a brand new
+// non-transactional query against the same *sql.DB must still succeed, proving database/sql dialed
+// a fresh connection rather than the pool being left wedged.
+func TestReconnect(t *testing.T) {
+	s := mustConnect(t)
+
+	mustTemporaryTable(t, s, func(tableName string) {
+		tx, err := s.Begin(context.Background(), nil)
+		if err != nil {
+			t.Fatal("unable to start transaction", err)
+		}
+
+		var connectionId int64
+		rows, err := tx.Query(context.Background(), vparam.New("SELECT CONNECTION_ID()"))
+		if err != nil {
+			t.Fatal("unable to query CONNECTION_ID()", err)
+		}
+		row := rows.Next()
+		if row == nil {
+			t.Fatal("expected a row from CONNECTION_ID()")
+		}
+		if err = row.Scan(&connectionId); err != nil {
+			t.Fatal("unable to scan CONNECTION_ID()", err)
+		}
+		_ = rows.Close()
+
+		if _, err = s.Exec(context.Background(), vparam.NewAppendWithData("KILL ?", connectionId)); err != nil {
+			t.Fatal("unable to kill the transaction's connection", err)
+		}
+
+		_, err = tx.Insert(context.Background(), vparam.NewAppendWithData("INSERT INTO `"+tableName+"` (name,age) VALUES (?,?)", "chris", 21))
+		if !errors.Is(err, ErrConnectionLost) {
+			t.Fatalf("expected ErrConnectionLost once the transaction's connection was killed, got %v", err)
+		}
+
+		after, err := s.Query(context.Background(), vparam.New("SELECT 1"))
+		if err != nil {
+			t.Fatal("expected the pool to reconnect for a fresh, non-transactional operation", err)
+		}
+		_ = after.Close()
+	})
+}
@@ -15,12 +15,9 @@ import (
 	"github.com/go-sql-driver/mysql"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/wojnosystems/vsql"
-	"github.com/wojnosystems/vsql/aggregator"
-	"github.com/wojnosystems/vsql/param"
+	"github.com/wojnosystems/vsql/vparam"
 	"github.com/wojnosystems/vsql/vquery"
-	"github.com/wojnosystems/vsql/vrow"
-	"github.com/wojnosystems/vsql/vrows"
-	"github.com/wojnosystems/vsql/vstmt"
+	"github.com/wojnosystems/vsql_mysql/vsqltest"
 	"os"
 	"strings"
 	"sync"
@@ -37,206 +34,20 @@ func TestMySQL_Ping(t *testing.T) {
 	}
 }
 
-// userRecord is a test record
-type userRecord struct {
-	name string
-	age  int
-}
-
-// Do multiple inserts and check that the values were inserted using select
-func TestMySQL_InsertQuery(t *testing.T) {
-	// rows to insert
-	data := []userRecord{
-		{
-			name: "chris",
-			age:  30,
-		},
-		{
-			name: "sam",
-			age:  25,
-		},
-		{
-			name: "brian",
-			age:  37,
+// TestConformance runs the shared vsqltest suite (the InsertQuery, Transaction_Commit/Rollback,
+// and TransactionStatement_Commit/Rollback behaviors formerly defined directly in this file)
+// against a real MySQL/MariaDB server; vsql_postgres and vsql_sqlite run the same suite against
+// their own databases.
+func TestConformance(t *testing.T) {
+	vsqltest.TestConformance(t, func(t *testing.T) vsql.SQLer {
+		return mustConnect(t)
+	}, vsqltest.Dialect{
+		Quote:                  vsql.BT,
+		AutoIncrementColumnDDL: "id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY",
+		CreateTableDDL: func(tableName, autoIncrementColumnDDL string) string {
+			return fmt.Sprint(`CREATE TABLE IF NOT EXISTS `, tableName,
+				` ( `, autoIncrementColumnDDL, `, name VARCHAR(255), age TINYINT UNSIGNED )`)
 		},
-	}
-
-	c := mustConnect(t)
-	mustTemporaryTable(t, c, func(tableName string) {
-		queryString := fmt.Sprint(`INSERT INTO `, vsql.BT(tableName), ` (name, age) VALUES (:name, :age)`)
-		for i := range data {
-			q := param.NewNamedWithData(queryString,
-				vsql.H{
-					"name": data[i].name,
-					"age":  data[i].age,
-				})
-			res, err := c.Insert(context.Background(), q)
-			if err != nil {
-				t.Fatal(err)
-			}
-			ra, err := res.RowsAffected()
-			if err != nil {
-				t.Fatal(err)
-			}
-			if ra != 1 {
-				t.Error("expected to insert a single row")
-			}
-		}
-
-		// Get the users back
-		results := make([]userRecord, 0, len(data))
-		queryString = fmt.Sprint(`SELECT name, age FROM `, vsql.BT(tableName), ` ORDER BY id`)
-		err := vrow.QueryEach(c,
-			context.Background(),
-			param.NewAppend(queryString),
-			func(r vrows.Rower) (stop bool, err error) {
-				ur := userRecord{}
-				err = r.Scan(&ur.name, &ur.age)
-				if err == nil {
-					results = append(results, ur)
-				}
-				return
-			})
-		if err != nil {
-			t.Error("QueryEach should not have returned an error, but did ", err)
-		}
-
-		// Ensure that we read 3 items:
-		if len(results) != len(data) {
-			t.Errorf(`Expected %d results, but got %d`, len(data), len(results))
-		}
-		for i := range data {
-			if data[i].name != results[i].name {
-				t.Errorf(`Data mis-match, expected name: "%s" got "%s"`, data[i].name, results[i].name)
-			}
-		}
-	})
-}
-
-// start a transaction, check that the value was persisted, rollback and ensure that the value is not longer visible
-func TestTransaction_Rollback(t *testing.T) {
-	// create a connection
-	c := mustConnect(t)
-
-	// create a table
-	mustTemporaryTable(t, c, func(tableName string) {
-		err := vsql.Txn(c, context.Background(), nil, func(tx vsql.QueryExecer) (commit bool, err error) {
-			_, err = tx.Insert(context.Background(), param.NewAppendWithData("INSERT INTO `"+tableName+"` (name,age) VALUES (?,?)", "chris", 21))
-			if err != nil {
-				t.Error("Error not expected when inserting data")
-				return false, err
-			}
-
-			count, err := aggregator.Count(context.Background(), tx, param.New("SELECT COUNT(*) FROM `"+tableName+"`"))
-			if err != nil {
-				t.Error("Error not expected when counting data")
-			}
-			if 1 != count {
-				t.Errorf(`Expected to insert 1 record, but inserted %d`, count)
-			}
-			return
-		})
-		if err != nil {
-			t.Fatal("error starting transaction")
-		}
-
-		count, err := aggregator.Count(context.Background(), c, param.New("SELECT COUNT(*) FROM `"+tableName+"`"))
-		if err != nil {
-			t.Error("Error not expected when counting data")
-		}
-		if 0 != count {
-			t.Errorf(`Expected to rollback the insert, but inserted %d`, count)
-		}
-	})
-}
-
-// start a transaction, check that the value was persisted, commit and ensure that the value is still visible
-func TestTransaction_Commit(t *testing.T) {
-	// create a connection
-	c := mustConnect(t)
-
-	// create a table
-	mustTemporaryTable(t, c, func(tableName string) {
-		err := vsql.Txn(c, context.Background(), nil, func(tx vsql.QueryExecer) (commit bool, err error) {
-			_, err = tx.Insert(context.Background(), param.NewAppendWithData("INSERT INTO `"+tableName+"` (name,age) VALUES (?,?)", "chris", 21))
-			if err != nil {
-				t.Error("Error not expected when inserting data")
-			}
-			return true, err
-		})
-		if err != nil {
-			t.Fatal("error starting transaction")
-		}
-
-		count, err := aggregator.Count(context.Background(), c, param.New("SELECT COUNT(*) FROM `"+tableName+"`"))
-		if err != nil {
-			t.Error("Error not expected when counting data")
-		}
-		if 1 != count {
-			t.Errorf(`Expected to commit the insert, but inserted %d`, count)
-		}
-	})
-}
-
-// start a transaction, build a prepared statement, insert a value, check that the value was persisted, commit and ensure that the value is still visible
-func TestTransactionStatement_Commit(t *testing.T) {
-	// create a connection
-	c := mustConnect(t)
-
-	// create a table
-	mustTemporaryTable(t, c, func(tableName string) {
-		err := vsql.Txn(c, context.Background(), nil, func(tx vsql.QueryExecer) (commit bool, err error) {
-			var s vstmt.Statementer
-			s, err = tx.Prepare(context.Background(), param.New("INSERT INTO `"+tableName+"` (name,age) VALUES (?,?)"))
-			if err != nil {
-				t.Fatal("Error not expected when preparing data")
-			}
-
-			_, err = s.Insert(context.Background(), param.NewAppendWithData("INSERT INTO `"+tableName+"` (name,age) VALUES (?,?)", "chris", 21))
-			return true, err
-		})
-		if err != nil {
-			t.Fatal("error starting transaction")
-		}
-
-		count, err := aggregator.Count(context.Background(), c, param.New("SELECT COUNT(*) FROM `"+tableName+"`"))
-		if err != nil {
-			t.Error("Error not expected when counting data")
-		}
-		if 1 != count {
-			t.Errorf(`Expected to commit the insert, but inserted %d`, count)
-		}
-	})
-}
-
-// start a transaction, build a prepared statement, insert a value, check that the value was persisted, rollback and ensure that the value is no longer visible
-func TestTransactionStatement_Rollback(t *testing.T) {
-	// create a connection
-	c := mustConnect(t)
-
-	// create a table
-	mustTemporaryTable(t, c, func(tableName string) {
-		err := vsql.Txn(c, context.Background(), nil, func(tx vsql.QueryExecer) (commit bool, err error) {
-			var s vstmt.Statementer
-			s, err = tx.Prepare(context.Background(), param.New("INSERT INTO `"+tableName+"` (name,age) VALUES (?,?)"))
-			if err != nil {
-				t.Fatal("Error not expected when preparing data")
-			}
-
-			_, err = s.Insert(context.Background(), param.NewAppendWithData("INSERT INTO `"+tableName+"` (name,age) VALUES (?,?)", "chris", 21))
-			return
-		})
-		if err != nil {
-			t.Fatal("error starting transaction")
-		}
-
-		count, err := aggregator.Count(context.Background(), c, param.New("SELECT COUNT(*) FROM `"+tableName+"`"))
-		if err != nil {
-			t.Error("Error not expected when counting data")
-		}
-		if 0 != count {
-			t.Errorf(`Expected to rollback the insert, but inserted %d`, count)
-		}
 	})
 }
 
@@ -247,7 +58,7 @@ func TestTransactionStatement_Rollback(t *testing.T) {
 // MYSQL_DBNAME: the database/schema to use
 //
 // Permissions: The MYSQL_USER you use needs to have the ability to add and remove tables
-func mustConnect(t *testing.T) (s vsql.SQLer) {
+func mustConnect(t testing.TB) (s vsql.SQLer) {
 	s = NewMySQL(func() (db *sql.DB) {
 		cfg := mysql.Config{
 			User:                 os.Getenv("MYSQL_USER"),
@@ -273,9 +84,9 @@ func mustConnect(t *testing.T) (s vsql.SQLer) {
 }
 
 // mustCreateTable creates a table with a "random" name (based on the current time) testing fatals are triggered if this fails
-func mustCreateTable(t *testing.T, execer vquery.Execer) (tableName string) {
+func mustCreateTable(t testing.TB, execer vquery.Execer) (tableName string) {
 	tableName = fmt.Sprintf("t%d", nextId())
-	_, err := execer.Exec(context.Background(), param.NewAppend(
+	_, err := execer.Exec(context.Background(), vparam.NewAppend(
 		fmt.Sprint(`CREATE TABLE IF NOT EXISTS `, tableName,
 			` ( id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY, name VARCHAR(255), age TINYINT UNSIGNED )`)))
 	if err != nil {
@@ -285,19 +96,19 @@ func mustCreateTable(t *testing.T, execer vquery.Execer) (tableName string) {
 }
 
 // mustDropTable deletes the table named tableName. testing fatals are triggered if this fails
-func mustDropTable(t *testing.T, execer vquery.Execer, tableName string) {
+func mustDropTable(t testing.TB, execer vquery.Execer, tableName string) {
 	if len(tableName) == 0 {
 		// do nothing
 		return
 	}
-	_, err := execer.Exec(context.Background(), param.NewNamedWithData("DROP TABLE `"+tableName+"`", vsql.H{"tableName": tableName}))
+	_, err := execer.Exec(context.Background(), vparam.NewNamedWithData("DROP TABLE `"+tableName+"`", vsql.H{"tableName": tableName}))
 	if err != nil {
 		t.Fatalf(`Unable to drop table named: "%s". Err: %#v`, tableName, err)
 	}
 }
 
 // mustTemporaryTable is a wrapper to create and "guarantee" clean up of the table used in the tests
-func mustTemporaryTable(t *testing.T, execer vquery.Execer, f func(tableName string)) {
+func mustTemporaryTable(t testing.TB, execer vquery.Execer, f func(tableName string)) {
 	tableName := mustCreateTable(t, execer)
 	defer mustDropTable(t, execer, tableName)
 	f(tableName)
@@ -0,0 +1,138 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package mysqlerr classifies the opaque errors MySQL's wire protocol returns into typed sentinels,
+// so callers can use errors.Is/errors.As instead of string-matching driver error text.
+package mysqlerr
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Sentinel errors for the MySQL server error numbers this package classifies. Wrap checks with
+// errors.Is, e.g. errors.Is(err, mysqlerr.ErrDeadlock).
+var (
+	// ErrDuplicateKey is MySQL error 1062 (ER_DUP_ENTRY): a UNIQUE index or PRIMARY KEY collision.
+	ErrDuplicateKey = errors.New("mysqlerr: duplicate key")
+	// ErrDeadlock is MySQL error 1213 (ER_LOCK_DEADLOCK): InnoDB chose this transaction as the
+	// deadlock victim and rolled it back. The transaction can usually be retried from the start.
+	ErrDeadlock = errors.New("mysqlerr: deadlock found when trying to get lock")
+	// ErrLockWaitTimeout is MySQL error 1205 (ER_LOCK_WAIT_TIMEOUT): the transaction waited longer
+	// than innodb_lock_wait_timeout for a row lock held by another transaction.
+	ErrLockWaitTimeout = errors.New("mysqlerr: lock wait timeout exceeded")
+	// ErrForeignKeyViolation is MySQL error 1216 (ER_NO_REFERENCED_ROW) or 1452
+	// (ER_NO_REFERENCED_ROW_2): the statement referenced a foreign key value with no matching row.
+	ErrForeignKeyViolation = errors.New("mysqlerr: foreign key constraint violation")
+	// ErrReadOnly is MySQL error 1290 (ER_OPTION_PREVENTS_STATEMENT): the server (or this
+	// connection's session) is running with --read-only and rejected a write.
+	ErrReadOnly = errors.New("mysqlerr: server is running in read-only mode")
+	// ErrTableNotFound is MySQL error 1146 (ER_NO_SUCH_TABLE): the statement referenced a table
+	// that doesn't exist in the current database.
+	ErrTableNotFound = errors.New("mysqlerr: table doesn't exist")
+	// ErrSyntax is MySQL error 1064 (ER_PARSE_ERROR): the server's SQL parser rejected the
+	// statement.
+	ErrSyntax = errors.New("mysqlerr: syntax error")
+)
+
+// sqlStatesByNumber maps the server error numbers above to the SQLSTATE the server would report
+// for them (go-sql-driver/mysql v1.4.1's MySQLError doesn't carry SQLSTATE itself), so
+// MySQLError.SQLState is populated even against this older driver version.
+var sqlStatesByNumber = map[uint16]string{
+	1062: "23000",
+	1213: "40001",
+	1205: "HY000",
+	1216: "23000",
+	1452: "23000",
+	1290: "HY000",
+	1146: "42S02",
+	1064: "42000",
+}
+
+// sentinelsByNumber maps each server error number this package classifies to its sentinel.
+var sentinelsByNumber = map[uint16]error{
+	1062: ErrDuplicateKey,
+	1213: ErrDeadlock,
+	1205: ErrLockWaitTimeout,
+	1216: ErrForeignKeyViolation,
+	1452: ErrForeignKeyViolation,
+	1290: ErrReadOnly,
+	1146: ErrTableNotFound,
+	1064: ErrSyntax,
+}
+
+// MySQLError is a classified MySQL server error. It implements error, Unwrap (to the sentinel it
+// was classified as, if any), and Is, so both errors.Is(err, mysqlerr.ErrDeadlock) and
+// errors.As(err, &mysqlErr) work against the error Classify returns.
+type MySQLError struct {
+	// Code is the MySQL server error number, e.g. 1062.
+	Code uint16
+	// SQLState is the ANSI SQL state associated with Code, e.g. "23000".
+	SQLState string
+	// Message is the server's error message text.
+	Message string
+
+	sentinel error
+}
+
+// Error implements error.
+func (e *MySQLError) Error() string {
+	return fmt.Sprintf("mysqlerr: %d (%s): %s", e.Code, e.SQLState, e.Message)
+}
+
+// Unwrap returns the sentinel error (ErrDuplicateKey, ErrDeadlock, etc.) this error was classified
+// as, or nil if Code isn't one this package recognizes.
+func (e *MySQLError) Unwrap() error {
+	return e.sentinel
+}
+
+// Is reports whether target is the sentinel this error was classified as, so
+// errors.Is(err, mysqlerr.ErrDeadlock) works without callers needing to know about Unwrap.
+func (e *MySQLError) Is(target error) bool {
+	return e.sentinel != nil && e.sentinel == target
+}
+
+// Classify wraps err in a *MySQLError if it is (or wraps) a *mysql.MySQLError whose Number this
+// package recognizes, so callers can branch on the sentinel with errors.Is instead of inspecting
+// err.Number or matching on err.Error() text. Classify returns err unchanged when it isn't a MySQL
+// server error, or when its Number isn't one of the codes this package classifies.
+func Classify(err error) error {
+	if err == nil {
+		return nil
+	}
+	var mErr *mysql.MySQLError
+	if !errors.As(err, &mErr) {
+		return err
+	}
+	sentinel, ok := sentinelsByNumber[mErr.Number]
+	if !ok {
+		return err
+	}
+	return &MySQLError{
+		Code:     mErr.Number,
+		SQLState: sqlStatesByNumber[mErr.Number],
+		Message:  mErr.Message,
+		sentinel: sentinel,
+	}
+}
+
+// IsRetryable reports whether err is a classified deadlock or lock-wait-timeout error - the two
+// cases where retrying the whole transaction from the start is the standard remedy, per the
+// InnoDB locking documentation.
+func IsRetryable(err error) bool {
+	return errors.Is(err, ErrDeadlock) || errors.Is(err, ErrLockWaitTimeout)
+}
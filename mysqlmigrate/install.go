@@ -0,0 +1,67 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package mysqlmigrate wires vsql_mysql's migrate.Runner into vsql_mysql.InstallMySQL. It lives in
+// its own package, separate from the root vsql_mysql package, so that callers who never run
+// migrations don't transitively pull in golang-migrate just by importing vsql_mysql - only callers
+// who import mysqlmigrate pay for that dependency.
+package mysqlmigrate
+
+import (
+	"database/sql"
+
+	"github.com/wojnosystems/vsql_engine"
+	"github.com/wojnosystems/vsql_mysql"
+	"github.com/wojnosystems/vsql_mysql/migrate"
+)
+
+// Options configures InstallMySQLWithMigrations.
+type Options struct {
+	// Source is a golang-migrate source URL, e.g. "file://migrations"
+	Source string
+}
+
+// WithMigrations is returned by InstallMySQLWithMigrations so callers can check the applied schema
+// version without pulling in the golang-migrate dependency themselves - it's the interface, not
+// migrate.Runner directly, that this package exposes.
+type WithMigrations interface {
+	// MigrationStatus reports the currently applied migration version and whether it's dirty
+	MigrationStatus() (version uint, dirty bool, err error)
+}
+
+type mySQLWithMigrations struct {
+	runner migrate.Runner
+}
+
+// MigrationStatus see WithMigrations
+func (m *mySQLWithMigrations) MigrationStatus() (version uint, dirty bool, err error) {
+	return m.runner.Status()
+}
+
+// InstallMySQLWithMigrations behaves like vsql_mysql.InstallMySQL, but first builds a
+// migrate.Migrator from opts and runs it Up before installing db onto engine. The returned
+// WithMigrations lets admin tooling check MigrationStatus() afterward without importing
+// golang-migrate directly.
+func InstallMySQLWithMigrations(engine vsql_engine.SingleTXer, db *sql.DB, opts Options) (WithMigrations, error) {
+	runner, err := migrate.New(db, migrate.Options{Source: opts.Source})
+	if err != nil {
+		return nil, err
+	}
+	if err = runner.Up(); err != nil {
+		return nil, err
+	}
+	vsql_mysql.InstallMySQL(engine, db)
+	return &mySQLWithMigrations{runner: runner}, nil
+}
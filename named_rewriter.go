@@ -0,0 +1,189 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package vsql_mysql
+
+import (
+	"fmt"
+
+	"github.com/wojnosystems/vsql"
+	"github.com/wojnosystems/vsql/interpolation_strategy"
+	"github.com/wojnosystems/vsql/vparam"
+)
+
+// RewriteNamedQuery parses `:name` placeholders out of sqlText and rewrites them into MySQL's "?"
+// positional placeholder, returning the matching []interface{} args slice built from data. This is
+// the same rewriting MySQLOptions.ClientSideNamedRewrite applies to every query NewMySQLWithOptions
+// runs; it is exported so callers who build SQL text outside of a vparam.Queryer (for example, to
+// hand to database/sql directly) can reuse it without going through a SQLer.
+func RewriteNamedQuery(sqlText string, data vsql.H) (rewritten string, args []interface{}, err error) {
+	return rewriteNamed(sqlText, data)
+}
+
+// rewriteNamed parses `:name` placeholders out of sqlText and rewrites them into MySQL's "?"
+// positional placeholder, building the matching []interface{} args slice from data in the order
+// the names are encountered (package scan's Named uses it too, via RewriteNamedQuery). It is aware
+// of single-quoted string literals, backtick-quoted identifiers, `--` and `/* */` comments, and
+// `::` casts, so `:` appearing inside any of those is left untouched instead of being mistaken for
+// a placeholder. A name referenced more than once is looked up once per occurrence but counted
+// once in the "referenced N times" sense callers care about for injection-safety auditing: the
+// same value is reused positionally every time the name recurs.
+func rewriteNamed(sqlText string, data vsql.H) (rewritten string, args []interface{}, err error) {
+	var out []byte
+	i := 0
+	n := len(sqlText)
+	for i < n {
+		c := sqlText[i]
+		switch {
+		case c == '\'':
+			end := skipLiteral(sqlText, i, '\'')
+			out = append(out, sqlText[i:end]...)
+			i = end
+		case c == '`':
+			end := skipLiteral(sqlText, i, '`')
+			out = append(out, sqlText[i:end]...)
+			i = end
+		case c == '-' && i+1 < n && sqlText[i+1] == '-':
+			end := skipLineComment(sqlText, i)
+			out = append(out, sqlText[i:end]...)
+			i = end
+		case c == '/' && i+1 < n && sqlText[i+1] == '*':
+			end := skipBlockComment(sqlText, i)
+			out = append(out, sqlText[i:end]...)
+			i = end
+		case c == ':' && i+1 < n && sqlText[i+1] == ':':
+			// a "::" cast, not a named placeholder
+			out = append(out, ':', ':')
+			i += 2
+		case c == ':':
+			j := i + 1
+			for j < n && isNameByte(sqlText[j]) {
+				j++
+			}
+			if j == i+1 {
+				out = append(out, c)
+				i++
+				continue
+			}
+			name := sqlText[i+1 : j]
+			val, ok := data[name]
+			if !ok {
+				return "", nil, fmt.Errorf("vsql_mysql: named parameter %q has no bound value", name)
+			}
+			out = append(out, '?')
+			args = append(args, val)
+			i = j
+		default:
+			out = append(out, c)
+			i++
+		}
+	}
+	return string(out), args, nil
+}
+
+func isNameByte(c byte) bool {
+	return c == '_' ||
+		(c >= 'a' && c <= 'z') ||
+		(c >= 'A' && c <= 'Z') ||
+		(c >= '0' && c <= '9')
+}
+
+// skipLiteral returns the index just past the closing quote matching quote, starting at a byte
+// that is quote. Doubled quotes ('' or ``) are treated as an escaped quote, not the terminator.
+func skipLiteral(s string, start int, quote byte) int {
+	i := start + 1
+	for i < len(s) {
+		if s[i] == quote {
+			if i+1 < len(s) && s[i+1] == quote {
+				i += 2
+				continue
+			}
+			return i + 1
+		}
+		if s[i] == '\\' && i+1 < len(s) {
+			i += 2
+			continue
+		}
+		i++
+	}
+	return i
+}
+
+func skipLineComment(s string, start int) int {
+	i := start
+	for i < len(s) && s[i] != '\n' {
+		i++
+	}
+	return i
+}
+
+func skipBlockComment(s string, start int) int {
+	i := start + 2
+	for i+1 < len(s) {
+		if s[i] == '*' && s[i+1] == '/' {
+			return i + 2
+		}
+		i++
+	}
+	return len(s)
+}
+
+// rawSQLer is implemented by vparam.Queryer values that retain their original `:name`-shaped SQL
+// text and the values bound to those names, so prepareNamed's MySQLOptions.ClientSideNamedRewrite
+// path can hand them to rewriteNamed directly instead of going through an
+// interpolation_strategy.InterpolateStrategy, which only knows how to produce positional
+// placeholders, not recover the names it replaced.
+type rawSQLer interface {
+	rawSQLAndData() (sqlText string, data vsql.H)
+}
+
+// namedQuery is the vparam.Queryer Named returns. Its Interpolate/SQLQuery methods run sqlText
+// through rewriteNamed so it behaves like any other vparam.Queryer when ClientSideNamedRewrite is
+// off, and it implements rawSQLer so prepareNamed can reach sqlText/data directly when it's on.
+type namedQuery struct {
+	sqlText string
+	data    vsql.H
+}
+
+// Named builds a vparam.Queryer from sqlText containing `:name` placeholders and the values to bind
+// them to, e.g. Named("SELECT * FROM users WHERE id = :id", vsql.H{"id": 42}). Pair it with
+// MySQLOptions.ClientSideNamedRewrite to route execution through rewriteNamed and a prepared
+// statement instead of NewMySQL's default textual interpolation.
+func Named(sqlText string, data vsql.H) vparam.Queryer {
+	return &namedQuery{sqlText: sqlText, data: data}
+}
+
+func (q *namedQuery) rawSQLAndData() (sqlText string, data vsql.H) {
+	return q.sqlText, q.data
+}
+
+// Interpolate see github.com/wojnosystems/vsql/vparam#Parameterer. sqlQuery is ignored: namedQuery
+// always rewrites its own q.sqlText, the same way vparam's Appender ignores its sqlQuery argument.
+func (q *namedQuery) Interpolate(sqlQuery string, strategy interpolation_strategy.InterpolateStrategy) (sqlOut string, args []interface{}, err error) {
+	return rewriteNamed(q.sqlText, q.data)
+}
+
+// SQLQueryUnInterpolated see github.com/wojnosystems/vsql/vparam#Queryer. Returns q.sqlText
+// unchanged; the `:name` placeholders are only rewritten once a strategy is available, in
+// SQLQueryInterpolated.
+func (q *namedQuery) SQLQueryUnInterpolated() string {
+	return q.sqlText
+}
+
+// SQLQueryInterpolated see github.com/wojnosystems/vsql/vparam#Queryer
+func (q *namedQuery) SQLQueryInterpolated(strategy interpolation_strategy.InterpolateStrategy) string {
+	sqlOut, _, _ := rewriteNamed(q.sqlText, q.data)
+	return sqlOut
+}
@@ -0,0 +1,117 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package vsql_mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wojnosystems/vsql"
+	"github.com/wojnosystems/vsql/vparam"
+)
+
+// a name referenced more than once is bound once and reused positionally for every occurrence
+func TestRewriteNamed_RepeatedName(t *testing.T) {
+	sqlText, args, err := rewriteNamed(`SELECT * FROM users WHERE id = :id OR parent_id = :id`, vsql.H{"id": 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sqlText != `SELECT * FROM users WHERE id = ? OR parent_id = ?` {
+		t.Errorf("unexpected rewritten SQL: %q", sqlText)
+	}
+	if len(args) != 2 || args[0] != 5 || args[1] != 5 {
+		t.Errorf("expected [5 5], got %#v", args)
+	}
+}
+
+// two distinct names that collide on a common prefix must not be confused with one another
+func TestRewriteNamed_NameCollisionPrefix(t *testing.T) {
+	sqlText, args, err := rewriteNamed(`SELECT * FROM users WHERE id = :id AND id2 = :id2`, vsql.H{"id": 1, "id2": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sqlText != `SELECT * FROM users WHERE id = ? AND id2 = ?` {
+		t.Errorf("unexpected rewritten SQL: %q", sqlText)
+	}
+	if len(args) != 2 || args[0] != 1 || args[1] != 2 {
+		t.Errorf("expected [1 2], got %#v", args)
+	}
+}
+
+// an unbound name must be reported as an error rather than silently left in the SQL text
+func TestRewriteNamed_UnboundName(t *testing.T) {
+	_, _, err := rewriteNamed(`SELECT * FROM users WHERE id = :id`, vsql.H{})
+	if err == nil {
+		t.Error("expected an error for an unbound named parameter")
+	}
+}
+
+// a ":" appearing inside a single-quoted or backtick-quoted literal is not a placeholder, so
+// injecting one through bound data cannot smuggle a second placeholder into the query
+func TestRewriteNamed_InjectionSafeQuoting(t *testing.T) {
+	sqlText, args, err := rewriteNamed("SELECT * FROM `t:odd` WHERE note = 'time :12:00' AND id = :id", vsql.H{"id": 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sqlText != "SELECT * FROM `t:odd` WHERE note = 'time :12:00' AND id = ?" {
+		t.Errorf("unexpected rewritten SQL: %q", sqlText)
+	}
+	if len(args) != 1 || args[0] != 7 {
+		t.Errorf("expected [7], got %#v", args)
+	}
+}
+
+// "--" line comments, "/* */" block comments, and "::" casts must pass through untouched
+func TestRewriteNamed_CommentsAndCasts(t *testing.T) {
+	sqlText, args, err := rewriteNamed("SELECT age::text, id -- comment with :not_a_param\nFROM users /* :also_not */ WHERE id = :id", vsql.H{"id": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT age::text, id -- comment with :not_a_param\nFROM users /* :also_not */ WHERE id = ?"
+	if sqlText != expected {
+		t.Errorf("unexpected rewritten SQL: %q", sqlText)
+	}
+	if len(args) != 1 || args[0] != 3 {
+		t.Errorf("expected [3], got %#v", args)
+	}
+}
+
+// Named's vparam.Queryer must actually run its SQL through rewriteNamed, not some other
+// placeholder scheme
+func TestNamed_InterpolateUsesRewriteNamed(t *testing.T) {
+	q := Named(`SELECT * FROM users WHERE id = :id`, vsql.H{"id": 9})
+	sqlText, args, err := q.Interpolate(`SELECT * FROM users WHERE id = :id`, &mySQLParamInterpolateStrategyDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sqlText != `SELECT * FROM users WHERE id = ?` {
+		t.Errorf("unexpected rewritten SQL: %q", sqlText)
+	}
+	if len(args) != 1 || args[0] != 9 {
+		t.Errorf("expected [9], got %#v", args)
+	}
+}
+
+// MySQLOptions.ClientSideNamedRewrite's execution path only understands queries built with Named,
+// since only those retain the raw SQL rewriteNamed needs; anything else must fail loudly rather
+// than silently falling back to ordinary textual interpolation
+func TestMySQL_PrepareNamed_RequiresNamedQuery(t *testing.T) {
+	m := &mySQL{opts: MySQLOptions{ClientSideNamedRewrite: true}}
+	_, _, err := m.prepareNamed(context.Background(), vparam.New(`SELECT 1`))
+	if err == nil {
+		t.Error("expected an error for a query not built with Named")
+	}
+}
@@ -0,0 +1,64 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package vsql_mysql
+
+import (
+	"database/sql"
+
+	"github.com/wojnosystems/vsql/ulong"
+)
+
+// sqlResult adapts a database/sql.Result into vresult.Resulter.
+type sqlResult struct {
+	res sql.Result
+}
+
+// RowsAffected see github.com/wojnosystems/vsql/vresult/results.go#Resulter
+func (r *sqlResult) RowsAffected() (ulong.ULong, error) {
+	if r.res == nil {
+		return ulong.New(0), nil
+	}
+	n, err := r.res.RowsAffected()
+	if err != nil {
+		return ulong.New(0), err
+	}
+	return ulong.NewInt64(n), nil
+}
+
+// sqlInsertResult is sqlResult plus LastInsertId, satisfying vresult.InsertResulter. Embedding
+// sqlResult lets exec()'s single return value satisfy both InsertResulter (Insert's return type)
+// and Resulter (Exec's return type).
+type sqlInsertResult struct {
+	sqlResult
+}
+
+// newSQLInsertResult wraps res, which may be nil when the caller hasn't run the exec yet (see
+// sqler.go's withRetry callbacks, which populate res only once the exec succeeds).
+func newSQLInsertResult(res sql.Result) *sqlInsertResult {
+	return &sqlInsertResult{sqlResult{res: res}}
+}
+
+// LastInsertId see github.com/wojnosystems/vsql/vresult/results.go#InsertResulter
+func (r *sqlInsertResult) LastInsertId() (ulong.ULong, error) {
+	if r.res == nil {
+		return ulong.New(0), nil
+	}
+	n, err := r.res.LastInsertId()
+	if err != nil {
+		return ulong.New(0), err
+	}
+	return ulong.NewInt64(n), nil
+}
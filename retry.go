@@ -0,0 +1,71 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package vsql_mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// ErrConnectionLost is returned by an in-flight mySQLtx when its underlying connection drops.
+// database/sql can transparently reconnect and retry a non-transactional query, but it cannot
+// recover an in-progress transaction: MySQL has already discarded whatever work happened under the
+// old connection, so the caller must start a new transaction rather than assume the old one is
+// still live.
+var ErrConnectionLost = errors.New("vsql_mysql: connection lost mid-transaction")
+
+// DefaultRetryPolicy is the MySQLOptions.RetryPolicy used when none is supplied. It reports true
+// for sql.ErrConnDone, driver.ErrBadConn, and the MySQL server error numbers 2006 ("server has gone
+// away") and 2013 ("lost connection during query") - the errors database/sql and the driver surface
+// when a pooled connection has died and a fresh one needs to take its place.
+func DefaultRetryPolicy(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) {
+		return true
+	}
+	var mErr *mysql.MySQLError
+	if errors.As(err, &mErr) {
+		return mErr.Number == 2006 || mErr.Number == 2013
+	}
+	return false
+}
+
+// retryPolicy returns opts.RetryPolicy, falling back to DefaultRetryPolicy for a zero-value
+// MySQLOptions (e.g. one built without going through NewMySQLWithOptions).
+func (o MySQLOptions) retryPolicy() func(err error) bool {
+	if o.RetryPolicy != nil {
+		return o.RetryPolicy
+	}
+	return DefaultRetryPolicy
+}
+
+// withRetry runs f once, and, if it fails with an error opts classifies as retryable, runs it a
+// single additional time as long as ctx has not already expired. database/sql itself discards the
+// dead pooled connection and dials a new one behind the scenes, so the retry is what actually gives
+// the caller a working connection rather than the same failure twice.
+func (o MySQLOptions) withRetry(ctx context.Context, f func() error) error {
+	err := f()
+	if err != nil && ctx.Err() == nil && o.retryPolicy()(err) {
+		err = f()
+	}
+	return err
+}
@@ -0,0 +1,170 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package vsql_mysql
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+
+	"github.com/wojnosystems/vsql/vparam"
+)
+
+// QueryStreamOptions configures QueryStream. It has no fields today; it exists so tuning knobs
+// can be added later without changing QueryStream's signature. There is deliberately no fetch-size
+// or server-side-cursor knob here: go-sql-driver/mysql (pinned at v1.4.1) has no useCursorFetch
+// DSN option or COM_STMT_FETCH support to tune, unlike, say, an ODBC-based MySQL client. QueryStream
+// already streams one row at a time off *sql.Rows - that's the driver pulling rows off its own
+// buffered connection as Next is called, not a true server-side cursor - which is as close to
+// "streaming" as this driver gets.
+type QueryStreamOptions struct {
+}
+
+// RowIterator streams the rows of a QueryStream result one at a time instead of buffering the
+// entire result set in memory, by calling *sql.Rows.Next()/Scan() incrementally rather than
+// reading every row up front. It wraps a single *sql.Rows plus a release func that closes the
+// prepared statement and cancels the context derived from the caller's ctx. Draining the iterator
+// with Next closes the *sql.Rows and returns its connection to the pool; the statement and the
+// derived context are released only by Close, which Scan calls itself once it stops.
+type RowIterator struct {
+	rows   *sql.Rows
+	cancel context.CancelFunc
+}
+
+// Next advances the iterator, mirroring database/sql.Rows.Next.
+func (it *RowIterator) Next() bool {
+	return it.rows.Next()
+}
+
+// Scan copies the current row's columns into dest, mirroring database/sql.Rows.Scan.
+func (it *RowIterator) Scan(dest ...interface{}) error {
+	return it.rows.Scan(dest...)
+}
+
+// Err returns the error, if any, encountered during iteration.
+func (it *RowIterator) Err() error {
+	return it.rows.Err()
+}
+
+// Close stops iteration and releases the underlying *sql.Rows and connection. It is safe to call
+// more than once and is always safe to call after the iterator has been fully drained.
+func (it *RowIterator) Close() error {
+	err := it.rows.Close()
+	it.cancel()
+	return err
+}
+
+// QueryStream runs query against m and returns a RowIterator that reads rows lazily off *sql.Rows
+// one at a time, instead of collecting the whole result set the way package scan's ScanAll does.
+// This is not a MySQL server-side cursor - go-sql-driver/mysql
+// has none to open - so it does not reduce round trips or let the server page results; it only
+// avoids holding every row in this process's memory at once. Cancelling ctx (or calling the
+// returned iterator's Close) eagerly releases the statement and connection rather than waiting for
+// the iterator to be drained.
+func (m *mySQL) QueryStream(ctx context.Context, query vparam.Queryer, opts QueryStreamOptions) (*RowIterator, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	q, ps, err := query.Interpolate(query.SQLQueryUnInterpolated(), &mySQLParamInterpolateStrategyDefault)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	stmt, err := m.db.PrepareContext(ctx, q)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	rows, err := stmt.QueryContext(ctx, ps...)
+	if err != nil {
+		_ = stmt.Close()
+		cancel()
+		return nil, err
+	}
+	return &RowIterator{
+		rows: rows,
+		cancel: func() {
+			_ = stmt.Close()
+			cancel()
+		},
+	}, nil
+}
+
+// Scan is a package-level generic helper, not a method on RowIterator: Go does not allow a method
+// to introduce type parameters of its own, so `it.Scan[T]()` as a method call isn't expressible and
+// this is the closest equivalent. It drains it into a channel of T, reflecting over each T's `db`
+// tags the same way package scan does, closing it (and therefore the underlying connection) once
+// the rows are exhausted, ctx is done, or a scan error occurs.
+func Scan[T any](ctx context.Context, it *RowIterator) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		defer it.Close()
+
+		cols, err := it.rows.Columns()
+		if err != nil {
+			return
+		}
+		var fieldsByTag map[string]int
+		for it.Next() {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			var v T
+			structVal := reflect.ValueOf(&v).Elem()
+			if fieldsByTag == nil {
+				fieldsByTag = rowStreamTagIndex(structVal.Type())
+			}
+			dests := make([]interface{}, len(cols))
+			for i, col := range cols {
+				fieldIdx, ok := fieldsByTag[col]
+				if !ok {
+					return
+				}
+				dests[i] = structVal.Field(fieldIdx).Addr().Interface()
+			}
+			if err = it.Scan(dests...); err != nil {
+				return
+			}
+
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// rowStreamTagIndex maps each `db:"col"` tag on t to its field index, the same convention package
+// scan's tagIndex uses; it's duplicated here rather than imported because scan's version is
+// unexported and Scan's reflection loop runs inline with RowIterator's fields.
+func rowStreamTagIndex(t reflect.Type) map[string]int {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	out := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("db")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		out[tag] = i
+	}
+	return out
+}
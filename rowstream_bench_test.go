@@ -0,0 +1,109 @@
+//Copyright 2019 Chris Wojno
+//
+//Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+//The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+//
+//THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package vsql_mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wojnosystems/vsql"
+	"github.com/wojnosystems/vsql/vparam"
+)
+
+// benchRowStreamRecord is the struct BenchmarkQueryStream_1MRows and BenchmarkQueryEach_1MRows both
+// scan into; only the columns mustCreateTable's DDL provides are used.
+type benchRowStreamRecord struct {
+	Name string `db:"name"`
+	Age  int    `db:"age"`
+}
+
+// mustSeedRows inserts n rows into tableName in multi-row INSERTs of up to 1000 rows each, before
+// the timer starts, so the benchmarks below spend their measured time scanning results, not
+// seeding them.
+func mustSeedRows(b *testing.B, s vsql.SQLer, tableName string, n int) {
+	const batch = 1000
+	for inserted := 0; inserted < n; inserted += batch {
+		count := batch
+		if remaining := n - inserted; remaining < batch {
+			count = remaining
+		}
+		q := "INSERT INTO `" + tableName + "` (name,age) VALUES "
+		args := make([]interface{}, 0, count*2)
+		for i := 0; i < count; i++ {
+			if i > 0 {
+				q += ","
+			}
+			q += "(?,?)"
+			args = append(args, "bench", 30)
+		}
+		if _, err := s.Exec(context.Background(), vparam.NewAppendWithData(q, args...)); err != nil {
+			b.Fatal("unable to seed benchmark rows", err)
+		}
+	}
+}
+
+// BenchmarkQueryStream_1MRows scans 1,000,000 rows through QueryStream/Scan, which holds at most
+// one decoded row in memory at a time, for comparison with BenchmarkQueryEach_1MRows.
+func BenchmarkQueryStream_1MRows(b *testing.B) {
+	s := mustConnect(b)
+	m, ok := s.(*mySQL)
+	if !ok {
+		b.Fatal("mustConnect did not return a *mySQL")
+	}
+
+	tableName := mustCreateTable(b, m)
+	defer mustDropTable(b, m, tableName)
+	mustSeedRows(b, m, tableName, 1000000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		iter, err := m.QueryStream(context.Background(), vparam.New("SELECT name, age FROM `"+tableName+"`"), QueryStreamOptions{})
+		if err != nil {
+			b.Fatal(err)
+		}
+		n := 0
+		for range Scan[benchRowStreamRecord](context.Background(), iter) {
+			n++
+		}
+		if n != 1000000 {
+			b.Fatalf("expected 1000000 rows, got %d", n)
+		}
+	}
+}
+
+// BenchmarkQueryEach_1MRows is QueryStream's baseline: the manual Query + rows.Next/Scan loop used
+// throughout this package before QueryStream existed. It also reads the *sql.Rows one row at a
+// time; unlike QueryStream it does not prepare a statement and scans into the struct fields by hand.
+func BenchmarkQueryEach_1MRows(b *testing.B) {
+	s := mustConnect(b)
+	tableName := mustCreateTable(b, s)
+	defer mustDropTable(b, s, tableName)
+	mustSeedRows(b, s, tableName, 1000000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rows, err := s.Query(context.Background(), vparam.New("SELECT name, age FROM `"+tableName+"`"))
+		if err != nil {
+			b.Fatal(err)
+		}
+		n := 0
+		for row := rows.Next(); row != nil; row = rows.Next() {
+			var r benchRowStreamRecord
+			if err = row.Scan(&r.Name, &r.Age); err != nil {
+				b.Fatal(err)
+			}
+			n++
+		}
+		if n != 1000000 {
+			b.Fatalf("expected 1000000 rows, got %d", n)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package scan
+
+import (
+	"github.com/wojnosystems/vsql"
+	"github.com/wojnosystems/vsql/interpolation_strategy"
+	"github.com/wojnosystems/vsql/vparam"
+	"github.com/wojnosystems/vsql_mysql"
+)
+
+// namedQuery is a vparam.Queryer that rewrites `:name` placeholders in sql into the strategy's
+// positional placeholder (e.g. "?" for MySQL), in the order they're encountered, pulling each
+// value out of data. The actual tokenizing is vsql_mysql.RewriteNamedQuery's - this package used to
+// carry its own naive, string-literal-unaware copy of the same rewrite, which is exactly the kind
+// of drift RewriteNamedQuery was exported to prevent.
+type namedQuery struct {
+	sql  string
+	data vsql.H
+}
+
+// Named builds a vparam.Queryer from sql containing `:name` tokens and the values to bind them to,
+// e.g. Named("SELECT * FROM users WHERE id = :user_id AND status = :status", vsql.H{"user_id": 42,
+// "status": "active"}). A name referenced more than once is bound once and reused positionally for
+// every occurrence.
+func Named(sql string, data map[string]interface{}) vparam.Queryer {
+	return &namedQuery{sql: sql, data: data}
+}
+
+// Interpolate see github.com/wojnosystems/vsql/vparam#Parameterer. sql is ignored: namedQuery
+// always rewrites its own q.sql, the same way vparam's Appender ignores its sqlQuery argument.
+// strategy is likewise unused: RewriteNamedQuery always emits "?", the only placeholder syntax
+// this package's callers (package scan is MySQL-only) ever need.
+func (q *namedQuery) Interpolate(sql string, strategy interpolation_strategy.InterpolateStrategy) (sqlOut string, args []interface{}, err error) {
+	return vsql_mysql.RewriteNamedQuery(q.sql, q.data)
+}
+
+// SQLQueryUnInterpolated see github.com/wojnosystems/vsql/vparam#Queryer. Returns q.sql unchanged;
+// the `:name` placeholders are only rewritten once a strategy is available, in SQLQueryInterpolated.
+func (q *namedQuery) SQLQueryUnInterpolated() string {
+	return q.sql
+}
+
+// SQLQueryInterpolated see github.com/wojnosystems/vsql/vparam#Queryer
+func (q *namedQuery) SQLQueryInterpolated(strategy interpolation_strategy.InterpolateStrategy) string {
+	sqlOut, _, _ := vsql_mysql.RewriteNamedQuery(q.sql, q.data)
+	return sqlOut
+}
@@ -0,0 +1,115 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package scan adds a struct-scan layer on top of vrows.Rowser and vparam.Queryer, similar in spirit
+// to jmoiron/sqlx, so callers don't have to hand-roll rows.Scan(&a, &b, ...) for every query.
+package scan
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/wojnosystems/vsql"
+	"github.com/wojnosystems/vsql/vparam"
+	"github.com/wojnosystems/vsql/vrows"
+)
+
+// ScanOne runs query against sqler and scans the first row into dst, which must be a non-nil
+// pointer to a struct whose fields are tagged `db:"col"`. When the query returns zero rows it
+// returns an error reading "scan: no rows returned"; that error is not sql.ErrNoRows.
+func ScanOne(ctx context.Context, sqler vsql.QueryExecer, query vparam.Queryer, dst interface{}) error {
+	rows, err := sqler.Query(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	row := rows.Next()
+	if row == nil {
+		return fmt.Errorf("scan: no rows returned")
+	}
+	return scanRowInto(row, row.Columns(), dst)
+}
+
+// ScanAll runs query against sqler and appends one struct per result row into dstSlice, which must
+// be a non-nil pointer to a slice of struct (or pointer-to-struct) values tagged `db:"col"`.
+func ScanAll(ctx context.Context, sqler vsql.QueryExecer, query vparam.Queryer, dstSlice interface{}) error {
+	rows, err := sqler.Query(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	slicePtr := reflect.ValueOf(dstSlice)
+	if slicePtr.Kind() != reflect.Ptr || slicePtr.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("scan: ScanAll dstSlice must be a pointer to a slice")
+	}
+	sliceVal := slicePtr.Elem()
+	elemType := sliceVal.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+
+	for row := rows.Next(); row != nil; row = rows.Next() {
+		elemPtr := reflect.New(elemType)
+		if err = scanRowInto(row, row.Columns(), elemPtr.Interface()); err != nil {
+			return err
+		}
+		if isPtr {
+			sliceVal.Set(reflect.Append(sliceVal, elemPtr))
+		} else {
+			sliceVal.Set(reflect.Append(sliceVal, elemPtr.Elem()))
+		}
+	}
+	return nil
+}
+
+// scanRowInto reflects over dst's `db` tags, builds Scan() destinations in column order, and Scans
+// the current row into them.
+func scanRowInto(row vrows.Rower, cols []string, dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("scan: destination must be a non-nil pointer")
+	}
+	structVal := v.Elem()
+	if structVal.Kind() != reflect.Struct {
+		return fmt.Errorf("scan: destination must point to a struct")
+	}
+
+	fieldsByTag := tagIndex(structVal.Type())
+	dests := make([]interface{}, len(cols))
+	for i, col := range cols {
+		fieldIdx, ok := fieldsByTag[col]
+		if !ok {
+			return fmt.Errorf("scan: no field tagged `db:%q` on %s", col, structVal.Type())
+		}
+		dests[i] = structVal.Field(fieldIdx).Addr().Interface()
+	}
+	return row.Scan(dests...)
+}
+
+// tagIndex maps each `db:"col"` tag on t to its field index
+func tagIndex(t reflect.Type) map[string]int {
+	out := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("db")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		out[tag] = i
+	}
+	return out
+}
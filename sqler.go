@@ -16,8 +16,16 @@
 package vsql_mysql
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"github.com/wojnosystems/vsql"
+	"github.com/wojnosystems/vsql/vparam"
+	"github.com/wojnosystems/vsql/vresult"
+	"github.com/wojnosystems/vsql/vrows"
+	"github.com/wojnosystems/vsql/vstmt"
+	"github.com/wojnosystems/vsql/vtxn"
+	"github.com/wojnosystems/vsql_mysql/mysqlerr"
 )
 
 // mySQL is largely a facade around Go's database/sql driver.
@@ -32,4 +40,114 @@ type mySQL struct {
 
 	// db is the database/sql.DB handle. This is composed in this object to hide the poor interface design and to make it easier to mock calls
 	db *sql.DB
+
+	// opts controls how Query/Insert/Exec interpolate and execute; see MySQLOptions
+	opts MySQLOptions
+}
+
+// Ping see github.com/wojnosystems/vsql/interfaces.go#Pinger
+func (m *mySQL) Ping(ctx context.Context) error {
+	return mysqlerr.Classify(m.db.PingContext(ctx))
+}
+
+// Begin see github.com/wojnosystems/vsql/transactions.go#TransactionStarter
+func (m *mySQL) Begin(ctx context.Context, opts vtxn.TxOptioner) (tx vsql.QueryExecTransactioner, err error) {
+	mTx := &mySQLtx{retryErr: m.opts.retryPolicy()}
+	mTx.tx, err = m.db.BeginTx(ctx, toSQLTxOptions(opts))
+	return mTx, err
+}
+
+// toSQLTxOptions converts opts to the database/sql shape BeginTx expects, treating a nil opts (the
+// zero value callers pass when they don't care about isolation level or read-only mode) the same
+// way BeginTx itself treats a nil *sql.TxOptions: driver defaults.
+func toSQLTxOptions(opts vtxn.TxOptioner) *sql.TxOptions {
+	if opts == nil {
+		return nil
+	}
+	return opts.ToTxOptions()
+}
+
+// Query see github.com/wojnosystems/vsql/interfaces.go#QueryExecer
+// A failed query is retried once, after database/sql has had a chance to reconnect, when
+// m.opts.RetryPolicy (DefaultRetryPolicy if unset) classifies the error as retryable.
+func (m *mySQL) Query(ctx context.Context, query vparam.Queryer) (rRows vrows.Rowser, err error) {
+	r := newSQLRows(nil)
+	err = m.opts.withRetry(ctx, func() error {
+		if m.opts.ClientSideNamedRewrite {
+			stmt, ps, cerr := m.prepareNamed(ctx, query)
+			if cerr != nil {
+				return cerr
+			}
+			defer stmt.Close()
+			r.rs, cerr = stmt.QueryContext(ctx, ps...)
+			return cerr
+		}
+		q, ps, cerr := query.Interpolate(query.SQLQueryUnInterpolated(), &mySQLParamInterpolateStrategyDefault)
+		if cerr != nil {
+			return cerr
+		}
+		r.rs, cerr = m.db.QueryContext(ctx, q, ps...)
+		return cerr
+	})
+	return r, mysqlerr.Classify(err)
+}
+
+// Insert see github.com/wojnosystems/vsql/interfaces.go#QueryExecer
+func (m *mySQL) Insert(ctx context.Context, query vparam.Queryer) (res vresult.InsertResulter, err error) {
+	return m.exec(ctx, query)
+}
+
+// Exec see github.com/wojnosystems/vsql/interfaces.go#QueryExecer
+func (m *mySQL) Exec(ctx context.Context, query vparam.Queryer) (res vresult.Resulter, err error) {
+	return m.exec(ctx, query)
+}
+
+// exec is Query's counterpart for statements that don't return rows, sharing the same retry
+// behavior.
+func (m *mySQL) exec(ctx context.Context, query vparam.Queryer) (res *sqlInsertResult, err error) {
+	sqlRes := newSQLInsertResult(nil)
+	err = m.opts.withRetry(ctx, func() error {
+		if m.opts.ClientSideNamedRewrite {
+			stmt, ps, cerr := m.prepareNamed(ctx, query)
+			if cerr != nil {
+				return cerr
+			}
+			defer stmt.Close()
+			sqlRes.res, cerr = stmt.ExecContext(ctx, ps...)
+			return cerr
+		}
+		q, ps, cerr := query.Interpolate(query.SQLQueryUnInterpolated(), &mySQLParamInterpolateStrategyDefault)
+		if cerr != nil {
+			return cerr
+		}
+		sqlRes.res, cerr = m.db.ExecContext(ctx, q, ps...)
+		return cerr
+	})
+	return sqlRes, mysqlerr.Classify(err)
+}
+
+// Prepare see github.com/wojnosystems/vsql/vstmt/statements.go#Preparer
+func (m *mySQL) Prepare(ctx context.Context, query vparam.Queryer) (stmtr vstmt.Statementer, err error) {
+	q := query.SQLQueryInterpolated(&mySQLParamInterpolateStrategyDefault)
+	mStmt := &mysqlStatement{}
+	mStmt.stmt, err = m.db.PrepareContext(ctx, q)
+	return mStmt, mysqlerr.Classify(err)
+}
+
+// prepareNamed runs query's original `:name`-shaped SQL through rewriteNamed, prepares the
+// resulting "?"-placeholder SQL fresh against m.db, and returns the args to execute it with - the
+// ClientSideNamedRewrite execution path every Query/Insert/Exec above shares. query must have been
+// built with Named; anything else doesn't retain the raw SQL rewriteNamed needs.
+func (m *mySQL) prepareNamed(ctx context.Context, query vparam.Queryer) (stmt *sql.Stmt, args []interface{}, err error) {
+	raw, ok := query.(rawSQLer)
+	if !ok {
+		return nil, nil, fmt.Errorf("vsql_mysql: MySQLOptions.ClientSideNamedRewrite requires a query built with vsql_mysql.Named, got %T", query)
+	}
+	sqlText, data := raw.rawSQLAndData()
+	q, args, err := rewriteNamed(sqlText, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	stmt, err = m.db.PrepareContext(ctx, q)
+	return stmt, args, err
 }
@@ -18,7 +18,7 @@ package vsql_mysql
 import (
 	"context"
 	"database/sql"
-	"github.com/wojnosystems/vsql/param"
+	"github.com/wojnosystems/vsql/vparam"
 	"github.com/wojnosystems/vsql/vresult"
 	"github.com/wojnosystems/vsql/vrows"
 	"github.com/wojnosystems/vsql/vstmt"
@@ -31,38 +31,38 @@ type mysqlStatement struct {
 }
 
 // Query see github.com/wojnosystems/vsql/vstmt/statements.go#Statementer
-func (m *mysqlStatement) Query(ctx context.Context, query param.Parameterer) (rRows vrows.Rowser, err error) {
+func (m *mysqlStatement) Query(ctx context.Context, query vparam.Parameterer) (rRows vrows.Rowser, err error) {
 	var ps []interface{}
-	sqlRes := &vrows.RowsImpl{}
-	_, ps, err = query.Interpolate(&mySQLParamInterpolateStrategyDefault)
+	sqlRes := newSQLRows(nil)
+	_, ps, err = query.Interpolate("", &mySQLParamInterpolateStrategyDefault)
 	if err != nil {
 		return sqlRes, err
 	}
-	sqlRes.SqlRows, err = m.stmt.QueryContext(ctx, ps...)
+	sqlRes.rs, err = m.stmt.QueryContext(ctx, ps...)
 	return sqlRes, err
 }
 
 // Insert see github.com/wojnosystems/vsql/vstmt/statements.go#Statementer
-func (m *mysqlStatement) Insert(ctx context.Context, query param.Parameterer) (res vresult.InsertResulter, err error) {
+func (m *mysqlStatement) Insert(ctx context.Context, query vparam.Parameterer) (res vresult.InsertResulter, err error) {
 	var ps []interface{}
-	sqlRes := &vresult.QueryResult{}
-	_, ps, err = query.Interpolate(&mySQLParamInterpolateStrategyDefault)
+	sqlRes := newSQLInsertResult(nil)
+	_, ps, err = query.Interpolate("", &mySQLParamInterpolateStrategyDefault)
 	if err != nil {
 		return sqlRes, err
 	}
-	sqlRes.SqlRes, err = m.stmt.ExecContext(ctx, ps...)
+	sqlRes.res, err = m.stmt.ExecContext(ctx, ps...)
 	return sqlRes, err
 }
 
 // Exec see github.com/wojnosystems/vsql/vstmt/statements.go#Statementer
-func (m *mysqlStatement) Exec(ctx context.Context, query param.Parameterer) (res vresult.Resulter, err error) {
+func (m *mysqlStatement) Exec(ctx context.Context, query vparam.Parameterer) (res vresult.Resulter, err error) {
 	var ps []interface{}
-	sqlRes := &vresult.QueryResult{}
-	_, ps, err = query.Interpolate(&mySQLParamInterpolateStrategyDefault)
+	sqlRes := newSQLInsertResult(nil)
+	_, ps, err = query.Interpolate("", &mySQLParamInterpolateStrategyDefault)
 	if err != nil {
 		return sqlRes, err
 	}
-	sqlRes.SqlRes, err = m.stmt.ExecContext(ctx, ps...)
+	sqlRes.res, err = m.stmt.ExecContext(ctx, ps...)
 	return sqlRes, err
 }
 
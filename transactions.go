@@ -12,70 +12,88 @@ import (
 	"context"
 	"database/sql"
 	"github.com/wojnosystems/vsql"
-	"github.com/wojnosystems/vsql/param"
+	"github.com/wojnosystems/vsql/vparam"
 	"github.com/wojnosystems/vsql/vresult"
 	"github.com/wojnosystems/vsql/vrows"
 	"github.com/wojnosystems/vsql/vstmt"
+	"github.com/wojnosystems/vsql_mysql/mysqlerr"
 )
 
 // mySQLtx is the internal model for transactions
 type mySQLtx struct {
 	vsql.QueryExecTransactioner
-	tx *sql.Tx
+	tx       *sql.Tx
+	retryErr func(err error) bool
+}
+
+// asConnectionLoss maps err to ErrConnectionLost when it looks like the connection under this
+// transaction died - database/sql can reconnect for a fresh, non-transactional query, but the
+// in-flight transaction is gone either way, so the caller needs a distinct, typed signal to start
+// over rather than retry the same *sql.Tx. Any other error is run through mysqlerr.Classify so
+// callers get a typed sentinel (ErrDeadlock, ErrDuplicateKey, etc.) instead of an opaque error.
+func (m *mySQLtx) asConnectionLoss(err error) error {
+	retryable := m.retryErr
+	if retryable == nil {
+		retryable = DefaultRetryPolicy
+	}
+	if retryable(err) {
+		return ErrConnectionLost
+	}
+	return mysqlerr.Classify(err)
 }
 
 // Commit see github.com/wojnosystems/vsql/transactions.go#Transactioner
 func (m *mySQLtx) Commit() error {
-	return m.tx.Commit()
+	return m.asConnectionLoss(m.tx.Commit())
 }
 
 // Rollback see github.com/wojnosystems/vsql/transactions.go#Transactioner
 func (m *mySQLtx) Rollback() error {
-	return m.tx.Rollback()
+	return m.asConnectionLoss(m.tx.Rollback())
 }
 
 // Query see github.com/wojnosystems/vsql/interfaces.go#QueryExecer
-func (m *mySQLtx) Query(ctx context.Context, query param.Queryer) (rRows vrows.Rowser, err error) {
+func (m *mySQLtx) Query(ctx context.Context, query vparam.Queryer) (rRows vrows.Rowser, err error) {
 	var q string
 	var ps []interface{}
-	r := &vrows.RowsImpl{}
-	q, ps, err = query.Interpolate(&mySQLParamInterpolateStrategyDefault)
+	r := newSQLRows(nil)
+	q, ps, err = query.Interpolate(query.SQLQueryUnInterpolated(), &mySQLParamInterpolateStrategyDefault)
 	if err != nil {
 		return r, err
 	}
-	r.SqlRows, err = m.tx.QueryContext(ctx, q, ps...)
-	return r, err
+	r.rs, err = m.tx.QueryContext(ctx, q, ps...)
+	return r, m.asConnectionLoss(err)
 }
 
 // Insert see github.com/wojnosystems/vsql/interfaces.go#QueryExecer
-func (m *mySQLtx) Insert(ctx context.Context, query param.Queryer) (res vresult.InsertResulter, err error) {
+func (m *mySQLtx) Insert(ctx context.Context, query vparam.Queryer) (res vresult.InsertResulter, err error) {
 	var q string
 	var ps []interface{}
-	sqlRes := &vresult.QueryResult{}
-	q, ps, err = query.Interpolate(&mySQLParamInterpolateStrategyDefault)
+	sqlRes := newSQLInsertResult(nil)
+	q, ps, err = query.Interpolate(query.SQLQueryUnInterpolated(), &mySQLParamInterpolateStrategyDefault)
 	if err != nil {
 		return sqlRes, err
 	}
-	sqlRes.SqlRes, err = m.tx.ExecContext(ctx, q, ps...)
-	return sqlRes, err
+	sqlRes.res, err = m.tx.ExecContext(ctx, q, ps...)
+	return sqlRes, m.asConnectionLoss(err)
 }
 
 // Exec see github.com/wojnosystems/vsql/interfaces.go#QueryExecer
-func (m *mySQLtx) Exec(ctx context.Context, query param.Queryer) (res vresult.Resulter, err error) {
+func (m *mySQLtx) Exec(ctx context.Context, query vparam.Queryer) (res vresult.Resulter, err error) {
 	var q string
 	var ps []interface{}
-	sqlRes := &vresult.QueryResult{}
-	q, ps, err = query.Interpolate(&mySQLParamInterpolateStrategyDefault)
+	sqlRes := newSQLInsertResult(nil)
+	q, ps, err = query.Interpolate(query.SQLQueryUnInterpolated(), &mySQLParamInterpolateStrategyDefault)
 	if err != nil {
 		return sqlRes, err
 	}
-	sqlRes.SqlRes, err = m.tx.ExecContext(ctx, q, ps...)
-	return sqlRes, err
+	sqlRes.res, err = m.tx.ExecContext(ctx, q, ps...)
+	return sqlRes, m.asConnectionLoss(err)
 }
 
 // Prepare see github.com/wojnosystems/vsql/vstmt/statements.go#Preparer
-func (m *mySQLtx) Prepare(ctx context.Context, query param.Queryer) (stmtr vstmt.Statementer, err error) {
-	q := query.SQLQuery(&mySQLParamInterpolateStrategyDefault)
+func (m *mySQLtx) Prepare(ctx context.Context, query vparam.Queryer) (stmtr vstmt.Statementer, err error) {
+	q := query.SQLQueryInterpolated(&mySQLParamInterpolateStrategyDefault)
 	mStmt := &mysqlStatementTx{
 		tx: m.tx,
 	}
@@ -93,38 +111,38 @@ type mysqlStatementTx struct {
 }
 
 // Query see github.com/wojnosystems/vsql/vstmt/statements.go#Statementer
-func (m *mysqlStatementTx) Query(ctx context.Context, query param.Parameterer) (rRows vrows.Rowser, err error) {
+func (m *mysqlStatementTx) Query(ctx context.Context, query vparam.Parameterer) (rRows vrows.Rowser, err error) {
 	var ps []interface{}
-	sqlRes := &vrows.RowsImpl{}
-	_, ps, err = query.Interpolate(&mySQLParamInterpolateStrategyDefault)
+	sqlRes := newSQLRows(nil)
+	_, ps, err = query.Interpolate("", &mySQLParamInterpolateStrategyDefault)
 	if err != nil {
 		return sqlRes, err
 	}
-	sqlRes.SqlRows, err = m.tx.StmtContext(ctx, m.stmt).QueryContext(ctx, ps...)
+	sqlRes.rs, err = m.tx.StmtContext(ctx, m.stmt).QueryContext(ctx, ps...)
 	return sqlRes, err
 }
 
 // Insert see github.com/wojnosystems/vsql/vstmt/statements.go#Statementer
-func (m *mysqlStatementTx) Insert(ctx context.Context, query param.Parameterer) (res vresult.InsertResulter, err error) {
+func (m *mysqlStatementTx) Insert(ctx context.Context, query vparam.Parameterer) (res vresult.InsertResulter, err error) {
 	var ps []interface{}
-	sqlRes := &vresult.QueryResult{}
-	_, ps, err = query.Interpolate(&mySQLParamInterpolateStrategyDefault)
+	sqlRes := newSQLInsertResult(nil)
+	_, ps, err = query.Interpolate("", &mySQLParamInterpolateStrategyDefault)
 	if err != nil {
 		return sqlRes, err
 	}
-	sqlRes.SqlRes, err = m.tx.StmtContext(ctx, m.stmt).ExecContext(ctx, ps...)
+	sqlRes.res, err = m.tx.StmtContext(ctx, m.stmt).ExecContext(ctx, ps...)
 	return sqlRes, err
 }
 
 // Exec see github.com/wojnosystems/vsql/vstmt/statements.go#Statementer
-func (m *mysqlStatementTx) Exec(ctx context.Context, query param.Parameterer) (res vresult.Resulter, err error) {
+func (m *mysqlStatementTx) Exec(ctx context.Context, query vparam.Parameterer) (res vresult.Resulter, err error) {
 	var ps []interface{}
-	sqlRes := &vresult.QueryResult{}
-	_, ps, err = query.Interpolate(&mySQLParamInterpolateStrategyDefault)
+	sqlRes := newSQLInsertResult(nil)
+	_, ps, err = query.Interpolate("", &mySQLParamInterpolateStrategyDefault)
 	if err != nil {
 		return sqlRes, err
 	}
-	sqlRes.SqlRes, err = m.tx.StmtContext(ctx, m.stmt).ExecContext(ctx, ps...)
+	sqlRes.res, err = m.tx.StmtContext(ctx, m.stmt).ExecContext(ctx, ps...)
 	return sqlRes, err
 }
 
@@ -0,0 +1,228 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package vsql_mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"github.com/wojnosystems/vsql"
+	"github.com/wojnosystems/vsql/vparam"
+	"github.com/wojnosystems/vsql/vresult"
+	"github.com/wojnosystems/vsql/vrows"
+	"github.com/wojnosystems/vsql/vstmt"
+	"github.com/wojnosystems/vsql/vtxn"
+)
+
+// mySQLtxNested is the internal model for a transaction that may be nested via SAVEPOINTs.
+//
+// depth 0 is the outermost, real *sql.Tx started with db.BeginTx. Every nested Begin against this
+// same tx issues a SAVEPOINT instead of asking the driver for a brand new *sql.Tx (MySQL connections
+// can only have one open transaction at a time). Commit/Rollback at depth>0 release/roll back to that
+// savepoint; only depth 0's Commit/Rollback touches the real *sql.Tx.
+type mySQLtxNested struct {
+	vsql.QueryExecTransactioner
+
+	// tx is the single, outermost *sql.Tx all savepoints within this nesting chain share
+	tx *sql.Tx
+
+	// depth is 0 for the outermost transaction, 1+ for each SAVEPOINT nested beneath it
+	depth int
+
+	// counter hands out the monotonic savepoint numbers; shared by every depth in the chain so
+	// sp_<n> names never collide even if Begin/Commit/Rollback are interleaved across goroutines
+	counter *uint64
+	counterMU *sync.Mutex
+
+	// name is the SAVEPOINT identifier for this transaction, empty at depth 0
+	name string
+}
+
+// nextSavepointName returns the next "sp_<n>" identifier for this nesting chain
+func (m *mySQLtxNested) nextSavepointName() string {
+	m.counterMU.Lock()
+	defer m.counterMU.Unlock()
+	*m.counter++
+	return fmt.Sprint("sp_", *m.counter)
+}
+
+// Begin see github.com/wojnosystems/vsql/transactions.go#TransactionNestedStarter. Issues a
+// SAVEPOINT against the shared *sql.Tx and returns a new mySQLtxNested one level deeper. opts is
+// accepted to satisfy the interface but ignored: MySQL has no notion of per-SAVEPOINT isolation
+// level or read-only mode, only the outermost BeginTx controls that for the whole chain.
+func (m *mySQLtxNested) Begin(ctx context.Context, opts vtxn.TxOptioner) (tx vsql.QueryExecTransactioner, err error) {
+	child := &mySQLtxNested{
+		tx:        m.tx,
+		depth:     m.depth + 1,
+		counter:   m.counter,
+		counterMU: m.counterMU,
+	}
+	child.name = child.nextSavepointName()
+	_, err = m.tx.ExecContext(ctx, "SAVEPOINT "+child.name)
+	if err != nil {
+		return nil, err
+	}
+	return child, nil
+}
+
+// Commit see github.com/wojnosystems/vsql/transactions.go#Transactioner. At depth 0 this commits the
+// real *sql.Tx; at any deeper level it releases the SAVEPOINT, keeping the outer transaction open.
+func (m *mySQLtxNested) Commit() error {
+	if m.depth == 0 {
+		return m.tx.Commit()
+	}
+	_, err := m.tx.Exec("RELEASE SAVEPOINT " + m.name)
+	return err
+}
+
+// Rollback see github.com/wojnosystems/vsql/transactions.go#Transactioner. At depth 0 this rolls back
+// the real *sql.Tx; at any deeper level it rolls back to the SAVEPOINT only, leaving everything
+// committed before the savepoint intact for the outer transaction to decide on.
+func (m *mySQLtxNested) Rollback() error {
+	if m.depth == 0 {
+		return m.tx.Rollback()
+	}
+	_, err := m.tx.Exec("ROLLBACK TO SAVEPOINT " + m.name)
+	return err
+}
+
+// Query see github.com/wojnosystems/vsql/interfaces.go#QueryExecer. Always routes through the
+// outermost *sql.Tx so results stay valid across savepoint boundaries.
+func (m *mySQLtxNested) Query(ctx context.Context, query vparam.Queryer) (rRows vrows.Rowser, err error) {
+	var q string
+	var ps []interface{}
+	r := newSQLRows(nil)
+	q, ps, err = query.Interpolate(query.SQLQueryUnInterpolated(), &mySQLParamInterpolateStrategyDefault)
+	if err != nil {
+		return r, err
+	}
+	r.rs, err = m.tx.QueryContext(ctx, q, ps...)
+	return r, err
+}
+
+// Insert see github.com/wojnosystems/vsql/interfaces.go#QueryExecer
+func (m *mySQLtxNested) Insert(ctx context.Context, query vparam.Queryer) (res vresult.InsertResulter, err error) {
+	var q string
+	var ps []interface{}
+	sqlRes := newSQLInsertResult(nil)
+	q, ps, err = query.Interpolate(query.SQLQueryUnInterpolated(), &mySQLParamInterpolateStrategyDefault)
+	if err != nil {
+		return sqlRes, err
+	}
+	sqlRes.res, err = m.tx.ExecContext(ctx, q, ps...)
+	return sqlRes, err
+}
+
+// Exec see github.com/wojnosystems/vsql/interfaces.go#QueryExecer
+func (m *mySQLtxNested) Exec(ctx context.Context, query vparam.Queryer) (res vresult.Resulter, err error) {
+	var q string
+	var ps []interface{}
+	sqlRes := newSQLInsertResult(nil)
+	q, ps, err = query.Interpolate(query.SQLQueryUnInterpolated(), &mySQLParamInterpolateStrategyDefault)
+	if err != nil {
+		return sqlRes, err
+	}
+	sqlRes.res, err = m.tx.ExecContext(ctx, q, ps...)
+	return sqlRes, err
+}
+
+// Prepare see github.com/wojnosystems/vsql/vstmt/statements.go#Preparer. The prepared statement is
+// bound to the outermost *sql.Tx so it remains usable after any inner savepoint is released or
+// rolled back.
+func (m *mySQLtxNested) Prepare(ctx context.Context, query vparam.Queryer) (stmtr vstmt.Statementer, err error) {
+	q := query.SQLQueryInterpolated(&mySQLParamInterpolateStrategyDefault)
+	mStmt := &mysqlStatementTx{
+		tx: m.tx,
+	}
+	mStmt.stmt, err = m.tx.PrepareContext(ctx, q)
+	return mStmt, err
+}
+
+// mySQLNested is largely a facade around Go's database/sql driver, same as mySQL, but it satisfies
+// vsql_engine.MultiTXer by issuing SAVEPOINTs for any Begin called while already inside a
+// transaction. Unlike mySQL, it implements vsql.SQLer directly instead of embedding it, since an
+// embedded nil interface would panic on every method this type doesn't override.
+type mySQLNested struct {
+	// db is the database/sql.DB handle, same role as mySQL.db
+	db *sql.DB
+}
+
+// Ping see github.com/wojnosystems/vsql/interfaces.go#Pinger
+func (m *mySQLNested) Ping(ctx context.Context) error {
+	return m.db.PingContext(ctx)
+}
+
+// Begin see github.com/wojnosystems/vsql/transactions.go#TransactionNestedStarter. Starts the real,
+// outermost *sql.Tx. Any Begin called on the returned mySQLtxNested nests via SAVEPOINT instead.
+func (m *mySQLNested) Begin(ctx context.Context, opts vtxn.TxOptioner) (tx vsql.QueryExecTransactioner, err error) {
+	root := &mySQLtxNested{
+		depth:     0,
+		counter:   new(uint64),
+		counterMU: &sync.Mutex{},
+	}
+	root.tx, err = m.db.BeginTx(ctx, toSQLTxOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// Query see github.com/wojnosystems/vsql/interfaces.go#QueryExecer
+func (m *mySQLNested) Query(ctx context.Context, query vparam.Queryer) (rRows vrows.Rowser, err error) {
+	r := newSQLRows(nil)
+	q, ps, err := query.Interpolate(query.SQLQueryUnInterpolated(), &mySQLParamInterpolateStrategyDefault)
+	if err != nil {
+		return r, err
+	}
+	r.rs, err = m.db.QueryContext(ctx, q, ps...)
+	return r, err
+}
+
+// Insert see github.com/wojnosystems/vsql/interfaces.go#QueryExecer
+func (m *mySQLNested) Insert(ctx context.Context, query vparam.Queryer) (res vresult.InsertResulter, err error) {
+	return m.exec(ctx, query)
+}
+
+// Exec see github.com/wojnosystems/vsql/interfaces.go#QueryExecer
+func (m *mySQLNested) Exec(ctx context.Context, query vparam.Queryer) (res vresult.Resulter, err error) {
+	return m.exec(ctx, query)
+}
+
+// exec is Query's counterpart for statements that don't return rows, shared by Insert and Exec.
+func (m *mySQLNested) exec(ctx context.Context, query vparam.Queryer) (res *sqlInsertResult, err error) {
+	sqlRes := newSQLInsertResult(nil)
+	q, ps, err := query.Interpolate(query.SQLQueryUnInterpolated(), &mySQLParamInterpolateStrategyDefault)
+	if err != nil {
+		return sqlRes, err
+	}
+	sqlRes.res, err = m.db.ExecContext(ctx, q, ps...)
+	return sqlRes, err
+}
+
+// Prepare see github.com/wojnosystems/vsql/vstmt/statements.go#Preparer
+func (m *mySQLNested) Prepare(ctx context.Context, query vparam.Queryer) (stmtr vstmt.Statementer, err error) {
+	q := query.SQLQueryInterpolated(&mySQLParamInterpolateStrategyDefault)
+	mStmt := &mysqlStatement{}
+	mStmt.stmt, err = m.db.PrepareContext(ctx, q)
+	return mStmt, err
+}
+
+// Close see github.com/wojnosystems/vsql/interfaces.go#SQLer. Closes the underlying *sql.DB pool.
+func (m *mySQLNested) Close() error {
+	return m.db.Close()
+}
@@ -0,0 +1,74 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package vsql_mysql
+
+import (
+	"context"
+	"time"
+
+	"github.com/wojnosystems/vsql"
+	"github.com/wojnosystems/vsql/vtxn"
+	"github.com/wojnosystems/vsql_mysql/mysqlerr"
+)
+
+// DeadlockRetryOptions configures TxnWithRetry's automatic retrying of deadlock and
+// lock-wait-timeout errors.
+type DeadlockRetryOptions struct {
+	// MaxAttempts is how many additional times to run block after it fails with a deadlock
+	// (mysqlerr.ErrDeadlock) or lock-wait-timeout (mysqlerr.ErrLockWaitTimeout) error. A zero value
+	// disables retrying - block runs exactly once, the same as vsql.Txn.
+	MaxAttempts int
+
+	// Backoff returns how long to sleep before the given retry attempt (1-indexed: the first retry
+	// is attempt 1). Leave nil to use DefaultDeadlockBackoff.
+	Backoff func(attempt int) time.Duration
+}
+
+// DefaultDeadlockBackoff is the DeadlockRetryOptions.Backoff used when none is supplied. It starts
+// at 10ms and doubles with each attempt, the exponential-backoff shape recommended for retrying
+// InnoDB deadlocks so a burst of contending transactions doesn't immediately collide again.
+func DefaultDeadlockBackoff(attempt int) time.Duration {
+	return 10 * time.Millisecond * time.Duration(uint(1)<<uint(attempt-1))
+}
+
+// backoff returns o.Backoff, falling back to DefaultDeadlockBackoff for a zero-value
+// DeadlockRetryOptions.
+func (o DeadlockRetryOptions) backoff() func(attempt int) time.Duration {
+	if o.Backoff != nil {
+		return o.Backoff
+	}
+	return DefaultDeadlockBackoff
+}
+
+// TxnWithRetry is vsql.Txn with automatic retrying of deadlock and lock-wait-timeout errors:
+// InnoDB's documented remedy for both is to retry the whole transaction from the start, since
+// whatever locks it held were released when the server rolled it back. Every other error -
+// including ErrConnectionLost, which needs a fresh transaction the caller controls rather than a
+// blind retry of the same block - is returned immediately, matching vsql.Txn's behavior.
+func TxnWithRetry(s vsql.SQLer, ctx context.Context, txOps vtxn.TxOptioner, opts DeadlockRetryOptions, block func(t vsql.QueryExecer) (commit bool, err error)) (err error) {
+	backoff := opts.backoff()
+	for attempt := 0; ; attempt++ {
+		err = vsql.Txn(s, ctx, txOps, block)
+		if err == nil || attempt >= opts.MaxAttempts || !mysqlerr.IsRetryable(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff(attempt + 1)):
+		}
+	}
+}
@@ -0,0 +1,42 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package vsql_postgres
+
+import (
+	"fmt"
+
+	"github.com/wojnosystems/vsql/interpolation_strategy"
+)
+
+// postgresParamInterpolateStrategy renders lib/pq's positional "$1, $2, ..." placeholders instead
+// of MySQL's "?". Unlike vsql_mysql's strategy, this one has to carry state: every call to
+// InsertPlaceholderIntoSQL advances the counter, so a new strategy value is needed per query rather
+// than a single shared default.
+type postgresParamInterpolateStrategy struct {
+	interpolation_strategy.InterpolateStrategy
+	n int
+}
+
+// newPostgresParamInterpolateStrategy returns a fresh, zeroed strategy for a single query
+func newPostgresParamInterpolateStrategy() *postgresParamInterpolateStrategy {
+	return &postgresParamInterpolateStrategy{}
+}
+
+// InsertPlaceholderIntoSQL injects the next "$n" placeholder
+func (p *postgresParamInterpolateStrategy) InsertPlaceholderIntoSQL() string {
+	p.n++
+	return fmt.Sprint("$", p.n)
+}
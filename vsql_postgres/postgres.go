@@ -0,0 +1,116 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package vsql_postgres is vsql_mysql's sibling for PostgreSQL: the same vsql.SQLer /
+// vquery.Execer / vstmt.Statementer interfaces, backed by lib/pq instead of go-sql-driver/mysql,
+// with "$1,$2,..." placeholders and double-quoted identifiers in place of MySQL's "?" and backticks.
+package vsql_postgres
+
+import (
+	"context"
+	"database/sql"
+
+	_ "github.com/lib/pq"
+	"github.com/wojnosystems/vsql"
+	"github.com/wojnosystems/vsql/vparam"
+	"github.com/wojnosystems/vsql/vresult"
+	"github.com/wojnosystems/vsql/vrows"
+	"github.com/wojnosystems/vsql/vstmt"
+	"github.com/wojnosystems/vsql/vtxn"
+)
+
+// postgres is largely a facade around Go's database/sql driver, the Postgres analog of
+// vsql_mysql's mySQL.
+type postgres struct {
+	vsql.SQLer
+	db *sql.DB
+}
+
+// NewPostgres creates a new Postgres connection manager. dbFactory is where you create your
+// traditional database/sql.DB object, same de-coupling rationale as vsql_mysql's InstallMySQL: as
+// long as queries and transactions operate the same way, the database version stays decoupled from
+// this interface. If dbFactory returns nil, NewPostgres returns nil.
+func NewPostgres(dbFactory func() *sql.DB) vsql.SQLer {
+	db := dbFactory()
+	if db == nil {
+		return nil
+	}
+	return &postgres{db: db}
+}
+
+// Ping see github.com/wojnosystems/vsql/interfaces.go#Pinger
+func (p *postgres) Ping(ctx context.Context) error {
+	return p.db.PingContext(ctx)
+}
+
+// Begin see github.com/wojnosystems/vsql/transactions.go#TransactionStarter
+func (p *postgres) Begin(ctx context.Context, opts vtxn.TxOptioner) (tx vsql.QueryExecTransactioner, err error) {
+	pTx := &postgresTx{}
+	pTx.tx, err = p.db.BeginTx(ctx, toSQLTxOptions(opts))
+	return pTx, err
+}
+
+// toSQLTxOptions converts opts to the database/sql shape BeginTx expects, treating a nil opts (the
+// zero value callers pass when they don't care about isolation level or read-only mode) the same
+// way BeginTx itself treats a nil *sql.TxOptions: driver defaults.
+func toSQLTxOptions(opts vtxn.TxOptioner) *sql.TxOptions {
+	if opts == nil {
+		return nil
+	}
+	return opts.ToTxOptions()
+}
+
+// Query see github.com/wojnosystems/vsql/interfaces.go#QueryExecer
+func (p *postgres) Query(ctx context.Context, query vparam.Queryer) (rRows vrows.Rowser, err error) {
+	var q string
+	var ps []interface{}
+	r := newSQLRows(nil)
+	q, ps, err = query.Interpolate(query.SQLQueryUnInterpolated(), newPostgresParamInterpolateStrategy())
+	if err != nil {
+		return r, err
+	}
+	r.rs, err = p.db.QueryContext(ctx, q, ps...)
+	return r, err
+}
+
+// Insert see github.com/wojnosystems/vsql/interfaces.go#QueryExecer
+func (p *postgres) Insert(ctx context.Context, query vparam.Queryer) (res vresult.InsertResulter, err error) {
+	return p.exec(ctx, query)
+}
+
+// Exec see github.com/wojnosystems/vsql/interfaces.go#QueryExecer
+func (p *postgres) Exec(ctx context.Context, query vparam.Queryer) (res vresult.Resulter, err error) {
+	return p.exec(ctx, query)
+}
+
+func (p *postgres) exec(ctx context.Context, query vparam.Queryer) (sqlRes *sqlInsertResult, err error) {
+	var q string
+	var ps []interface{}
+	sqlRes = newSQLInsertResult(nil)
+	q, ps, err = query.Interpolate(query.SQLQueryUnInterpolated(), newPostgresParamInterpolateStrategy())
+	if err != nil {
+		return sqlRes, err
+	}
+	sqlRes.res, err = p.db.ExecContext(ctx, q, ps...)
+	return sqlRes, err
+}
+
+// Prepare see github.com/wojnosystems/vsql/vstmt/statements.go#Preparer
+func (p *postgres) Prepare(ctx context.Context, query vparam.Queryer) (stmtr vstmt.Statementer, err error) {
+	q := query.SQLQueryInterpolated(newPostgresParamInterpolateStrategy())
+	pStmt := &postgresStatement{}
+	pStmt.stmt, err = p.db.PrepareContext(ctx, q)
+	return pStmt, err
+}
@@ -0,0 +1,52 @@
+//Copyright 2019 Chris Wojno
+//
+//Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+//The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+//
+//THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package vsql_postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/wojnosystems/vsql"
+	"github.com/wojnosystems/vsql_mysql/vsqltest"
+)
+
+// TestConformance runs the shared vsqltest suite (Ping, InsertQuery, Transaction_Commit/Rollback,
+// TransactionStatement_Commit/Rollback) against a real Postgres server, configured with the same
+// PG_* environment variables vsql_mysql's tests use the MYSQL_* equivalents for.
+func TestConformance(t *testing.T) {
+	vsqltest.TestConformance(t, func(t *testing.T) vsql.SQLer {
+		return mustConnect(t)
+	}, vsqltest.Dialect{
+		Quote:                  DQ,
+		AutoIncrementColumnDDL: "id SERIAL PRIMARY KEY",
+		CreateTableDDL: func(tableName, autoIncrementColumnDDL string) string {
+			return fmt.Sprint(`CREATE TABLE IF NOT EXISTS `, tableName,
+				` ( `, autoIncrementColumnDDL, `, name VARCHAR(255), age SMALLINT )`)
+		},
+	})
+}
+
+// mustConnect creates a database connection to a Postgres server as indicated by the following OS
+// Environment variables: PG_USER, PG_PASSWORD, PG_HOST, PG_PORT, PG_DBNAME, PG_SSLMODE
+func mustConnect(t *testing.T) (s vsql.SQLer) {
+	s = NewPostgres(func() (db *sql.DB) {
+		dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+			os.Getenv("PG_HOST"), os.Getenv("PG_PORT"), os.Getenv("PG_USER"),
+			os.Getenv("PG_PASSWORD"), os.Getenv("PG_DBNAME"), os.Getenv("PG_SSLMODE"))
+		db, err := sql.Open("postgres", dsn)
+		if err != nil {
+			t.Fatal("unable to initialize the Postgres driver", err)
+			return nil
+		}
+		return
+	})
+	return
+}
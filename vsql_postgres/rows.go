@@ -0,0 +1,63 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package vsql_postgres
+
+import (
+	"database/sql"
+
+	"github.com/wojnosystems/vsql/vrows"
+)
+
+// sqlRows adapts a *sql.Rows into vrows.Rowser/vrows.Rower, the same way vsql_mysql's sqlRows does:
+// a single value plays both roles, Next returning itself (or nil, once exhausted).
+type sqlRows struct {
+	rs   *sql.Rows
+	cols []string
+}
+
+// newSQLRows wraps rs, which may be nil when the caller hasn't run the query yet.
+func newSQLRows(rs *sql.Rows) *sqlRows {
+	return &sqlRows{rs: rs}
+}
+
+// Next see github.com/wojnosystems/vsql/vrows/rows.go#Rowser
+func (r *sqlRows) Next() vrows.Rower {
+	if r.rs == nil || !r.rs.Next() {
+		return nil
+	}
+	return r
+}
+
+// Columns see github.com/wojnosystems/vsql/vrows/rows.go#Rower
+func (r *sqlRows) Columns() []string {
+	if r.cols == nil && r.rs != nil {
+		r.cols, _ = r.rs.Columns()
+	}
+	return r.cols
+}
+
+// Scan see github.com/wojnosystems/vsql/vrows/rows.go#Rower
+func (r *sqlRows) Scan(dest ...interface{}) error {
+	return r.rs.Scan(dest...)
+}
+
+// Close see io.Closer
+func (r *sqlRows) Close() error {
+	if r.rs == nil {
+		return nil
+	}
+	return r.rs.Close()
+}
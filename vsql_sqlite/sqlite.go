@@ -0,0 +1,114 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package vsql_sqlite is another vsql_mysql sibling, this time backed by mattn/go-sqlite3, for
+// running the same vsql.SQLer-shaped code against an embedded database in tests or small tools.
+package vsql_sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/wojnosystems/vsql"
+	"github.com/wojnosystems/vsql/vparam"
+	"github.com/wojnosystems/vsql/vresult"
+	"github.com/wojnosystems/vsql/vrows"
+	"github.com/wojnosystems/vsql/vstmt"
+	"github.com/wojnosystems/vsql/vtxn"
+)
+
+// sqlite is largely a facade around Go's database/sql driver, the SQLite analog of vsql_mysql's
+// mySQL and vsql_postgres's postgres.
+type sqlite struct {
+	vsql.SQLer
+	db *sql.DB
+}
+
+// NewSQLite creates a new SQLite connection manager. dbFactory is where you create your
+// traditional database/sql.DB object, same de-coupling rationale as vsql_mysql's InstallMySQL and
+// vsql_postgres's NewPostgres. If dbFactory returns nil, NewSQLite returns nil.
+func NewSQLite(dbFactory func() *sql.DB) vsql.SQLer {
+	db := dbFactory()
+	if db == nil {
+		return nil
+	}
+	return &sqlite{db: db}
+}
+
+// Ping see github.com/wojnosystems/vsql/interfaces.go#Pinger
+func (s *sqlite) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// Begin see github.com/wojnosystems/vsql/transactions.go#TransactionStarter
+func (s *sqlite) Begin(ctx context.Context, opts vtxn.TxOptioner) (tx vsql.QueryExecTransactioner, err error) {
+	sTx := &sqliteTx{}
+	sTx.tx, err = s.db.BeginTx(ctx, toSQLTxOptions(opts))
+	return sTx, err
+}
+
+// toSQLTxOptions converts opts to the database/sql shape BeginTx expects, treating a nil opts (the
+// zero value callers pass when they don't care about isolation level or read-only mode) the same
+// way BeginTx itself treats a nil *sql.TxOptions: driver defaults.
+func toSQLTxOptions(opts vtxn.TxOptioner) *sql.TxOptions {
+	if opts == nil {
+		return nil
+	}
+	return opts.ToTxOptions()
+}
+
+// Query see github.com/wojnosystems/vsql/interfaces.go#QueryExecer
+func (s *sqlite) Query(ctx context.Context, query vparam.Queryer) (rRows vrows.Rowser, err error) {
+	var q string
+	var ps []interface{}
+	r := newSQLRows(nil)
+	q, ps, err = query.Interpolate(query.SQLQueryUnInterpolated(), &sqliteParamInterpolateStrategyDefault)
+	if err != nil {
+		return r, err
+	}
+	r.rs, err = s.db.QueryContext(ctx, q, ps...)
+	return r, err
+}
+
+// Insert see github.com/wojnosystems/vsql/interfaces.go#QueryExecer
+func (s *sqlite) Insert(ctx context.Context, query vparam.Queryer) (res vresult.InsertResulter, err error) {
+	return s.exec(ctx, query)
+}
+
+// Exec see github.com/wojnosystems/vsql/interfaces.go#QueryExecer
+func (s *sqlite) Exec(ctx context.Context, query vparam.Queryer) (res vresult.Resulter, err error) {
+	return s.exec(ctx, query)
+}
+
+func (s *sqlite) exec(ctx context.Context, query vparam.Queryer) (sqlRes *sqlInsertResult, err error) {
+	var q string
+	var ps []interface{}
+	sqlRes = newSQLInsertResult(nil)
+	q, ps, err = query.Interpolate(query.SQLQueryUnInterpolated(), &sqliteParamInterpolateStrategyDefault)
+	if err != nil {
+		return sqlRes, err
+	}
+	sqlRes.res, err = s.db.ExecContext(ctx, q, ps...)
+	return sqlRes, err
+}
+
+// Prepare see github.com/wojnosystems/vsql/vstmt/statements.go#Preparer
+func (s *sqlite) Prepare(ctx context.Context, query vparam.Queryer) (stmtr vstmt.Statementer, err error) {
+	q := query.SQLQueryInterpolated(&sqliteParamInterpolateStrategyDefault)
+	sStmt := &sqliteStatement{}
+	sStmt.stmt, err = s.db.PrepareContext(ctx, q)
+	return sStmt, err
+}
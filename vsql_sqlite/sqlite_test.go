@@ -0,0 +1,40 @@
+//Copyright 2019 Chris Wojno
+//
+//Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+//The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+//
+//THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package vsql_sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/wojnosystems/vsql"
+	"github.com/wojnosystems/vsql_mysql/vsqltest"
+)
+
+// TestConformance proves vsql_sqlite behaves the same as vsql_mysql and vsql_postgres for the
+// operations vsql cares about, using an in-memory database so no external server is required.
+func TestConformance(t *testing.T) {
+	vsqltest.TestConformance(t, func(t *testing.T) vsql.SQLer {
+		return NewSQLite(func() (db *sql.DB) {
+			db, err := sql.Open("sqlite3", "file::memory:?cache=shared")
+			if err != nil {
+				t.Fatal("unable to initialize the SQLite driver", err)
+				return nil
+			}
+			return
+		})
+	}, vsqltest.Dialect{
+		Quote: DQ,
+		AutoIncrementColumnDDL: "id INTEGER PRIMARY KEY AUTOINCREMENT",
+		CreateTableDDL: func(tableName, autoIncrementColumnDDL string) string {
+			return fmt.Sprint(`CREATE TEMPORARY TABLE IF NOT EXISTS `, tableName,
+				` ( `, autoIncrementColumnDDL, `, name VARCHAR(255), age INTEGER )`)
+		},
+	})
+}
@@ -0,0 +1,74 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package vsql_sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/wojnosystems/vsql/vparam"
+	"github.com/wojnosystems/vsql/vresult"
+	"github.com/wojnosystems/vsql/vrows"
+	"github.com/wojnosystems/vsql/vstmt"
+)
+
+// sqliteStatement is a representation of a prepared statement that is NOT running in the context
+// of a transaction. Same role as vsql_mysql's mysqlStatement.
+type sqliteStatement struct {
+	vstmt.Statementer
+	stmt *sql.Stmt
+}
+
+// Query see github.com/wojnosystems/vsql/vstmt/statements.go#Statementer
+func (p *sqliteStatement) Query(ctx context.Context, query vparam.Parameterer) (rRows vrows.Rowser, err error) {
+	var ps []interface{}
+	sqlRes := newSQLRows(nil)
+	_, ps, err = query.Interpolate("", &sqliteParamInterpolateStrategyDefault)
+	if err != nil {
+		return sqlRes, err
+	}
+	sqlRes.rs, err = p.stmt.QueryContext(ctx, ps...)
+	return sqlRes, err
+}
+
+// Insert see github.com/wojnosystems/vsql/vstmt/statements.go#Statementer
+func (p *sqliteStatement) Insert(ctx context.Context, query vparam.Parameterer) (res vresult.InsertResulter, err error) {
+	var ps []interface{}
+	sqlRes := newSQLInsertResult(nil)
+	_, ps, err = query.Interpolate("", &sqliteParamInterpolateStrategyDefault)
+	if err != nil {
+		return sqlRes, err
+	}
+	sqlRes.res, err = p.stmt.ExecContext(ctx, ps...)
+	return sqlRes, err
+}
+
+// Exec see github.com/wojnosystems/vsql/vstmt/statements.go#Statementer
+func (p *sqliteStatement) Exec(ctx context.Context, query vparam.Parameterer) (res vresult.Resulter, err error) {
+	var ps []interface{}
+	sqlRes := newSQLInsertResult(nil)
+	_, ps, err = query.Interpolate("", &sqliteParamInterpolateStrategyDefault)
+	if err != nil {
+		return sqlRes, err
+	}
+	sqlRes.res, err = p.stmt.ExecContext(ctx, ps...)
+	return sqlRes, err
+}
+
+// Close see github.com/wojnosystems/vsql/vstmt/statements.go#Statementer
+func (p *sqliteStatement) Close() error {
+	return p.stmt.Close()
+}
@@ -0,0 +1,140 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package vsql_sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/wojnosystems/vsql"
+	"github.com/wojnosystems/vsql/vparam"
+	"github.com/wojnosystems/vsql/vresult"
+	"github.com/wojnosystems/vsql/vrows"
+	"github.com/wojnosystems/vsql/vstmt"
+)
+
+// sqliteTx is the internal model for transactions, mirroring vsql_mysql's mySQLtx
+type sqliteTx struct {
+	vsql.QueryExecTransactioner
+	tx *sql.Tx
+}
+
+// Commit see github.com/wojnosystems/vsql/transactions.go#Transactioner
+func (p *sqliteTx) Commit() error {
+	return p.tx.Commit()
+}
+
+// Rollback see github.com/wojnosystems/vsql/transactions.go#Transactioner
+func (p *sqliteTx) Rollback() error {
+	return p.tx.Rollback()
+}
+
+// Query see github.com/wojnosystems/vsql/interfaces.go#QueryExecer
+func (p *sqliteTx) Query(ctx context.Context, query vparam.Queryer) (rRows vrows.Rowser, err error) {
+	var q string
+	var ps []interface{}
+	r := newSQLRows(nil)
+	q, ps, err = query.Interpolate(query.SQLQueryUnInterpolated(), &sqliteParamInterpolateStrategyDefault)
+	if err != nil {
+		return r, err
+	}
+	r.rs, err = p.tx.QueryContext(ctx, q, ps...)
+	return r, err
+}
+
+// Insert see github.com/wojnosystems/vsql/interfaces.go#QueryExecer
+func (p *sqliteTx) Insert(ctx context.Context, query vparam.Queryer) (res vresult.InsertResulter, err error) {
+	var q string
+	var ps []interface{}
+	sqlRes := newSQLInsertResult(nil)
+	q, ps, err = query.Interpolate(query.SQLQueryUnInterpolated(), &sqliteParamInterpolateStrategyDefault)
+	if err != nil {
+		return sqlRes, err
+	}
+	sqlRes.res, err = p.tx.ExecContext(ctx, q, ps...)
+	return sqlRes, err
+}
+
+// Exec see github.com/wojnosystems/vsql/interfaces.go#QueryExecer
+func (p *sqliteTx) Exec(ctx context.Context, query vparam.Queryer) (res vresult.Resulter, err error) {
+	var q string
+	var ps []interface{}
+	sqlRes := newSQLInsertResult(nil)
+	q, ps, err = query.Interpolate(query.SQLQueryUnInterpolated(), &sqliteParamInterpolateStrategyDefault)
+	if err != nil {
+		return sqlRes, err
+	}
+	sqlRes.res, err = p.tx.ExecContext(ctx, q, ps...)
+	return sqlRes, err
+}
+
+// Prepare see github.com/wojnosystems/vsql/vstmt/statements.go#Preparer
+func (p *sqliteTx) Prepare(ctx context.Context, query vparam.Queryer) (stmtr vstmt.Statementer, err error) {
+	q := query.SQLQueryInterpolated(&sqliteParamInterpolateStrategyDefault)
+	pStmt := &sqliteStatementTx{
+		tx: p.tx,
+	}
+	pStmt.stmt, err = p.tx.PrepareContext(ctx, q)
+	return pStmt, err
+}
+
+// sqliteStatementTx is a prepared statement within the context of a transaction
+type sqliteStatementTx struct {
+	vstmt.Statementer
+	stmt *sql.Stmt
+	tx   *sql.Tx
+}
+
+// Query see github.com/wojnosystems/vsql/vstmt/statements.go#Statementer
+func (p *sqliteStatementTx) Query(ctx context.Context, query vparam.Parameterer) (rRows vrows.Rowser, err error) {
+	var ps []interface{}
+	sqlRes := newSQLRows(nil)
+	_, ps, err = query.Interpolate("", &sqliteParamInterpolateStrategyDefault)
+	if err != nil {
+		return sqlRes, err
+	}
+	sqlRes.rs, err = p.tx.StmtContext(ctx, p.stmt).QueryContext(ctx, ps...)
+	return sqlRes, err
+}
+
+// Insert see github.com/wojnosystems/vsql/vstmt/statements.go#Statementer
+func (p *sqliteStatementTx) Insert(ctx context.Context, query vparam.Parameterer) (res vresult.InsertResulter, err error) {
+	var ps []interface{}
+	sqlRes := newSQLInsertResult(nil)
+	_, ps, err = query.Interpolate("", &sqliteParamInterpolateStrategyDefault)
+	if err != nil {
+		return sqlRes, err
+	}
+	sqlRes.res, err = p.tx.StmtContext(ctx, p.stmt).ExecContext(ctx, ps...)
+	return sqlRes, err
+}
+
+// Exec see github.com/wojnosystems/vsql/vstmt/statements.go#Statementer
+func (p *sqliteStatementTx) Exec(ctx context.Context, query vparam.Parameterer) (res vresult.Resulter, err error) {
+	var ps []interface{}
+	sqlRes := newSQLInsertResult(nil)
+	_, ps, err = query.Interpolate("", &sqliteParamInterpolateStrategyDefault)
+	if err != nil {
+		return sqlRes, err
+	}
+	sqlRes.res, err = p.tx.StmtContext(ctx, p.stmt).ExecContext(ctx, ps...)
+	return sqlRes, err
+}
+
+// Close see github.com/wojnosystems/vsql/vstmt/statements.go#Statementer
+func (p *sqliteStatementTx) Close() error {
+	return p.stmt.Close()
+}
@@ -0,0 +1,268 @@
+//Copyright 2019 Chris Wojno
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of the
+// Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package vsqltest is a reusable conformance suite for anything implementing vsql.SQLer. Each
+// driver package (vsql_mysql, vsql_postgres, vsql_sqlite, ...) calls TestConformance from its own
+// `go test` with a Factory and a Dialect describing that database's quirks, proving the three
+// drivers behave identically for the operations vsql cares about, in the spirit of the Dex
+// storage/sql pattern of one suite run across multiple databases.
+package vsqltest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wojnosystems/vsql"
+	"github.com/wojnosystems/vsql/aggregator"
+	"github.com/wojnosystems/vsql/vparam"
+	"github.com/wojnosystems/vsql/vquery"
+)
+
+// Factory builds a fresh connection to the database under test. TestConformance calls it once per
+// subtest, mirroring how vsql_mysql's mustConnect/NewMySQL is called once per test.
+type Factory func(t *testing.T) vsql.SQLer
+
+// Dialect captures the per-database differences TestConformance needs to generate valid DDL/DML:
+// identifier quoting, the auto-increment column syntax, and how to declare a temporary table.
+type Dialect struct {
+	// Quote wraps an identifier (table name) the way this database expects: backticks for MySQL,
+	// double quotes for Postgres/SQLite
+	Quote func(identifier string) string
+
+	// AutoIncrementColumnDDL is the full column definition for this suite's auto-incrementing
+	// primary key, e.g. "id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY" for MySQL or
+	// "id INTEGER PRIMARY KEY AUTOINCREMENT" for SQLite
+	AutoIncrementColumnDDL string
+
+	// CreateTableDDL, given a table name and AutoIncrementColumnDDL, returns the full
+	// "CREATE TABLE ..." statement for this dialect
+	CreateTableDDL func(tableName, autoIncrementColumnDDL string) string
+}
+
+// TestConformance runs the same set of behaviors vsql_mysql's original tests covered - Ping,
+// InsertQuery, Transaction_Commit/Rollback, TransactionStatement_Commit/Rollback - against
+// whatever factory/dialect the caller supplies.
+func TestConformance(t *testing.T, factory Factory, dialect Dialect) {
+	t.Run("Ping", func(t *testing.T) {
+		if err := factory(t).Ping(context.Background()); err != nil {
+			t.Error("Unable to ping the database server", err)
+		}
+	})
+
+	t.Run("InsertQuery", func(t *testing.T) {
+		testInsertQuery(t, factory(t), dialect)
+	})
+
+	t.Run("Transaction_Commit", func(t *testing.T) {
+		testTransactionCommit(t, factory(t), dialect)
+	})
+
+	t.Run("Transaction_Rollback", func(t *testing.T) {
+		testTransactionRollback(t, factory(t), dialect)
+	})
+
+	t.Run("TransactionStatement_Commit", func(t *testing.T) {
+		testTransactionStatementCommit(t, factory(t), dialect)
+	})
+
+	t.Run("TransactionStatement_Rollback", func(t *testing.T) {
+		testTransactionStatementRollback(t, factory(t), dialect)
+	})
+}
+
+type userRecord struct {
+	name string
+	age  int
+}
+
+func testInsertQuery(t *testing.T, c vsql.SQLer, dialect Dialect) {
+	data := []userRecord{
+		{name: "chris", age: 30},
+		{name: "sam", age: 25},
+		{name: "brian", age: 37},
+	}
+
+	mustTemporaryTable(t, c, dialect, func(tableName string) {
+		insertSQL := fmt.Sprint(`INSERT INTO `, dialect.Quote(tableName), ` (name, age) VALUES (?, ?)`)
+		for i := range data {
+			_, err := c.Insert(context.Background(), vparam.NewAppendWithData(insertSQL, data[i].name, data[i].age))
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		results := make([]userRecord, 0, len(data))
+		selectSQL := fmt.Sprint(`SELECT name, age FROM `, dialect.Quote(tableName), ` ORDER BY id`)
+		rows, err := c.Query(context.Background(), vparam.NewAppend(selectSQL))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for row := rows.Next(); row != nil; row = rows.Next() {
+			ur := userRecord{}
+			if err = row.Scan(&ur.name, &ur.age); err != nil {
+				t.Fatal(err)
+			}
+			results = append(results, ur)
+		}
+
+		if len(results) != len(data) {
+			t.Errorf(`Expected %d results, but got %d`, len(data), len(results))
+		}
+		for i := range data {
+			if i < len(results) && data[i].name != results[i].name {
+				t.Errorf(`Data mis-match, expected name: "%s" got "%s"`, data[i].name, results[i].name)
+			}
+		}
+	})
+}
+
+func testTransactionCommit(t *testing.T, c vsql.SQLer, dialect Dialect) {
+	mustTemporaryTable(t, c, dialect, func(tableName string) {
+		insertSQL := `INSERT INTO ` + dialect.Quote(tableName) + ` (name,age) VALUES (?,?)`
+		err := vsql.Txn(c, context.Background(), nil, func(tx vsql.QueryExecer) (commit bool, err error) {
+			_, err = tx.Insert(context.Background(), vparam.NewAppendWithData(insertSQL, "chris", 21))
+			return true, err
+		})
+		if err != nil {
+			t.Fatal("error starting transaction", err)
+		}
+
+		count, err := aggregator.Count(context.Background(), c, vparam.New(`SELECT COUNT(*) FROM `+dialect.Quote(tableName)))
+		if err != nil {
+			t.Error("Error not expected when counting data")
+		}
+		if 1 != count {
+			t.Errorf(`Expected to commit the insert, but inserted %d`, count)
+		}
+	})
+}
+
+func testTransactionRollback(t *testing.T, c vsql.SQLer, dialect Dialect) {
+	mustTemporaryTable(t, c, dialect, func(tableName string) {
+		insertSQL := `INSERT INTO ` + dialect.Quote(tableName) + ` (name,age) VALUES (?,?)`
+		err := vsql.Txn(c, context.Background(), nil, func(tx vsql.QueryExecer) (commit bool, err error) {
+			_, err = tx.Insert(context.Background(), vparam.NewAppendWithData(insertSQL, "chris", 21))
+			return false, err
+		})
+		if err != nil {
+			t.Fatal("error starting transaction", err)
+		}
+
+		count, err := aggregator.Count(context.Background(), c, vparam.New(`SELECT COUNT(*) FROM `+dialect.Quote(tableName)))
+		if err != nil {
+			t.Error("Error not expected when counting data")
+		}
+		if 0 != count {
+			t.Errorf(`Expected to rollback the insert, but inserted %d`, count)
+		}
+	})
+}
+
+func testTransactionStatementCommit(t *testing.T, c vsql.SQLer, dialect Dialect) {
+	mustTemporaryTable(t, c, dialect, func(tableName string) {
+		insertSQL := `INSERT INTO ` + dialect.Quote(tableName) + ` (name,age) VALUES (?,?)`
+		err := vsql.Txn(c, context.Background(), nil, func(tx vsql.QueryExecer) (commit bool, err error) {
+			s, err := tx.Prepare(context.Background(), vparam.New(insertSQL))
+			if err != nil {
+				t.Fatal("Error not expected when preparing data", err)
+			}
+			_, err = s.Insert(context.Background(), vparam.NewAppendWithData(insertSQL, "chris", 21))
+			return true, err
+		})
+		if err != nil {
+			t.Fatal("error starting transaction", err)
+		}
+
+		count, err := aggregator.Count(context.Background(), c, vparam.New(`SELECT COUNT(*) FROM `+dialect.Quote(tableName)))
+		if err != nil {
+			t.Error("Error not expected when counting data")
+		}
+		if 1 != count {
+			t.Errorf(`Expected to commit the insert, but inserted %d`, count)
+		}
+	})
+}
+
+func testTransactionStatementRollback(t *testing.T, c vsql.SQLer, dialect Dialect) {
+	mustTemporaryTable(t, c, dialect, func(tableName string) {
+		insertSQL := `INSERT INTO ` + dialect.Quote(tableName) + ` (name,age) VALUES (?,?)`
+		err := vsql.Txn(c, context.Background(), nil, func(tx vsql.QueryExecer) (commit bool, err error) {
+			s, err := tx.Prepare(context.Background(), vparam.New(insertSQL))
+			if err != nil {
+				t.Fatal("Error not expected when preparing data", err)
+			}
+			_, err = s.Insert(context.Background(), vparam.NewAppendWithData(insertSQL, "chris", 21))
+			return false, err
+		})
+		if err != nil {
+			t.Fatal("error starting transaction", err)
+		}
+
+		count, err := aggregator.Count(context.Background(), c, vparam.New(`SELECT COUNT(*) FROM `+dialect.Quote(tableName)))
+		if err != nil {
+			t.Error("Error not expected when counting data")
+		}
+		if 0 != count {
+			t.Errorf(`Expected to rollback the insert, but inserted %d`, count)
+		}
+	})
+}
+
+// mustCreateTable creates a table with a "random" name (based on the current time)
+func mustCreateTable(t *testing.T, execer vquery.Execer, dialect Dialect) (tableName string) {
+	tableName = fmt.Sprintf("t%d", nextId())
+	ddl := dialect.CreateTableDDL(tableName, dialect.AutoIncrementColumnDDL)
+	_, err := execer.Exec(context.Background(), vparam.NewAppend(ddl))
+	if err != nil {
+		t.Fatalf(`Unable to create table named: "%s". Err: %#v`, tableName, err)
+	}
+	return
+}
+
+// mustDropTable deletes the table named tableName
+func mustDropTable(t *testing.T, execer vquery.Execer, dialect Dialect, tableName string) {
+	if len(tableName) == 0 {
+		return
+	}
+	_, err := execer.Exec(context.Background(), vparam.New(`DROP TABLE `+dialect.Quote(tableName)))
+	if err != nil {
+		t.Fatalf(`Unable to drop table named: "%s". Err: %#v`, tableName, err)
+	}
+}
+
+// mustTemporaryTable is a wrapper to create and "guarantee" clean up of the table used in the tests
+func mustTemporaryTable(t *testing.T, execer vquery.Execer, dialect Dialect, f func(tableName string)) {
+	tableName := mustCreateTable(t, execer, dialect)
+	defer mustDropTable(t, execer, dialect, tableName)
+	f(tableName)
+}
+
+// nextId gets the next monotonically increasing ID in the set
+func nextId() int64 {
+	uniqueIdMU.Lock()
+	defer uniqueIdMU.Unlock()
+	uniqueId++
+	return uniqueId
+}
+
+var uniqueId int64
+var uniqueIdMU sync.Mutex
+
+func init() {
+	uniqueId = time.Now().Unix()
+}